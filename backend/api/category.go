@@ -89,20 +89,26 @@ func GetHotCategories(c context.Context, ctx *app.RequestContext) {
 // CreateCategory 创建分类
 func CreateCategory(c context.Context, ctx *app.RequestContext) {
 	var req struct {
-		Name        string `json:"name" vd:"len($)>0 && len($)<=50"`
-		Slug        string `json:"slug" vd:"len($)>0 && len($)<=50"`
-		Description string `json:"description,omitempty" vd:"len($)<=255"`
+		Name        string         `json:"name" vd:"len($)>0 && len($)<=50"`
+		Slug        string         `json:"slug" vd:"len($)>0 && len($)<=50"`
+		Description string         `json:"description,omitempty" vd:"len($)<=255"`
+		SEO         models.SEOMeta `json:"seo"`
 	}
 
 	if err := ctx.BindAndValidate(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
 		return
 	}
+	if err := validateSEOMeta(req.SEO); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": err.Error()})
+		return
+	}
 
 	category := models.Category{
 		Name:        req.Name,
 		Slug:        req.Slug,
 		Description: req.Description,
+		SEO:         req.SEO,
 	}
 
 	if err := config.DB.Create(&category).Error; err != nil {
@@ -124,15 +130,22 @@ func UpdateCategory(c context.Context, ctx *app.RequestContext) {
 	}
 
 	var req struct {
-		Name        string `json:"name,omitempty" vd:"len($)<=50"`
-		Slug        string `json:"slug,omitempty" vd:"len($)<=50"`
-		Description string `json:"description,omitempty" vd:"len($)<=255"`
+		Name        string          `json:"name,omitempty" vd:"len($)<=50"`
+		Slug        string          `json:"slug,omitempty" vd:"len($)<=50"`
+		Description string          `json:"description,omitempty" vd:"len($)<=255"`
+		SEO         *models.SEOMeta `json:"seo"` // 不为nil时整体替换 SEO 元数据
 	}
 
 	if err := ctx.BindAndValidate(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
 		return
 	}
+	if req.SEO != nil {
+		if err := validateSEOMeta(*req.SEO); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": err.Error()})
+			return
+		}
+	}
 
 	var category models.Category
 	if err := config.DB.First(&category, id).Error; err != nil {
@@ -154,6 +167,11 @@ func UpdateCategory(c context.Context, ctx *app.RequestContext) {
 	if req.Description != "" {
 		updates["description"] = req.Description
 	}
+	if req.SEO != nil {
+		for column, value := range seoColumns(*req.SEO) {
+			updates[column] = value
+		}
+	}
 
 	if len(updates) > 0 {
 		if err := config.DB.Model(&category).Updates(updates).Error; err != nil {
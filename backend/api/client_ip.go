@@ -0,0 +1,44 @@
+package api
+
+import (
+	"log"
+	"net"
+	"strings"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// trustedProxyCIDRs 解析 TRUSTED_PROXIES 配置的可信反向代理，支持单个IP和CIDR网段，无效项记录日志后忽略
+func trustedProxyCIDRs() []*net.IPNet {
+	var cidrs []*net.IPNet
+	for _, item := range strings.Split(config.GetEnv("TRUSTED_PROXIES", ""), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			if ip := net.ParseIP(item); ip != nil && ip.To4() != nil {
+				item += "/32"
+			} else {
+				item += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			log.Printf("忽略无效的可信代理配置 %q: %v", item, err)
+			continue
+		}
+		cidrs = append(cidrs, network)
+	}
+	return cidrs
+}
+
+// NewClientIPFunc 创建获取客户端IP的函数：只有直连地址属于可信代理时才采信 X-Forwarded-For / X-Real-IP，
+// 未配置可信代理时始终使用连接的远端地址，避免客户端伪造请求头绕过按IP的检测
+func NewClientIPFunc() app.ClientIP {
+	return app.ClientIPWithOption(app.ClientIPOptions{
+		RemoteIPHeaders: []string{"X-Forwarded-For", "X-Real-IP"},
+		TrustedCIDRs:    trustedProxyCIDRs(),
+	})
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net"
+	"testing"
+)
+
+func TestTrustedProxyCIDRs(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		trusted []string
+		others  []string
+	}{
+		{"未配置时不信任任何地址", "", nil, []string{"127.0.0.1", "10.0.0.1", "::1"}},
+		{"单个IPv4地址", "10.0.0.1", []string{"10.0.0.1"}, []string{"10.0.0.2"}},
+		{"单个IPv6地址", "::1", []string{"::1"}, []string{"::2"}},
+		{"CIDR网段", "172.16.0.0/12, 127.0.0.1", []string{"172.20.1.1", "127.0.0.1"}, []string{"192.168.1.1"}},
+		{"忽略无效项", "bogus,10.0.0.0/8", []string{"10.9.9.9"}, []string{"11.0.0.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tt.env)
+			cidrs := trustedProxyCIDRs()
+			contains := func(ip string) bool {
+				for _, cidr := range cidrs {
+					if cidr.Contains(net.ParseIP(ip)) {
+						return true
+					}
+				}
+				return false
+			}
+			for _, ip := range tt.trusted {
+				if !contains(ip) {
+					t.Errorf("%s should be trusted", ip)
+				}
+			}
+			for _, ip := range tt.others {
+				if contains(ip) {
+					t.Errorf("%s should not be trusted", ip)
+				}
+			}
+		})
+	}
+}
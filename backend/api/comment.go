@@ -3,35 +3,129 @@ package api
 import (
 	"context"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/alvinhmg/blog/config"
 	"github.com/alvinhmg/blog/models"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
 )
 
-// GetPostComments 获取文章评论列表
+// GetPostComments 获取文章已通过审核的评论树，按顶层评论分页
+// 查询参数: sort (oldest/newest/top，top 按赞同数排序)、depth (加载的层数，含顶层)、limit、cursor，
+// 以及可选的 parent_id，用于继续加载某条评论下超出深度的回复
 func GetPostComments(c context.Context, ctx *app.RequestContext) {
 	// 获取文章ID
-	postID := ctx.Param("postId")
+	postID, err := strconv.ParseUint(ctx.Param("postId"), 10, 64)
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的文章ID",
+		})
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.Where("status = ?", models.PostStatusPublished).First(&post, postID).Error; err != nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "文章不存在",
+		})
+		return
+	}
+
+	sort := ctx.DefaultQuery("sort", commentSortOldest)
+	if sort != commentSortOldest && sort != commentSortNewest && sort != commentSortTop {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的排序方式，可选 oldest、newest、top",
+		})
+		return
+	}
+	depth, err := strconv.Atoi(ctx.DefaultQuery("depth", "3"))
+	if err != nil || depth < 1 {
+		depth = 3
+	}
+	if depth > maxCommentDepth() {
+		depth = maxCommentDepth()
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var cursor *commentCursor
+	if raw := ctx.Query("cursor"); raw != "" {
+		if cursor, err = decodeCommentCursor(raw, sort); err != nil {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	// 查询一层已通过审核的评论 (默认为顶层评论)
+	db := config.DB.Model(&models.Comment{}).Where("comment.post_id = ? AND comment.status = ?", post.ID, models.CommentStatusApproved)
+	if raw := ctx.Query("parent_id"); raw != "" {
+		parentID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的父评论ID",
+			})
+			return
+		}
+		db = db.Where("comment.parent_id = ?", parentID)
+	} else {
+		db = db.Where("comment.parent_id IS NULL")
+	}
+
+	var total int64
+	db.Count(&total)
 
-	// 查询评论列表（只返回已审核通过的评论）
+	// 多查一条用于判断是否还有下一页
 	var comments []models.Comment
-	result := config.DB.Where("post_id = ? AND (status = 'approved' OR status IS NULL)", postID).Preload("User").Order("created_at DESC").Find(&comments)
-	if result.Error != nil {
+	if err := db.Scopes(publicCommentFields, commentThreadScope(sort, cursor)).Preload("User", publicUserFields).Limit(limit + 1).Find(&comments).Error; err != nil {
 		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
 			"code":    500,
 			"message": "获取评论列表失败",
-			"error":   result.Error.Error(),
+			"error":   err.Error(),
+		})
+		return
+	}
+	hasMore := len(comments) > limit
+	if hasMore {
+		comments = comments[:limit]
+	}
+
+	if err := loadCommentReplies(comments, depth-1); err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取评论回复失败",
+			"error":   err.Error(),
 		})
 		return
 	}
+	fillCommentReactions(ctx, comments)
 
-	// 返回评论列表
+	nextCursor := ""
+	if hasMore {
+		nextCursor = encodeCommentCursor(sort, comments[len(comments)-1])
+	}
+
+	// 返回评论树
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
 		"code":    200,
 		"message": "获取评论列表成功",
-		"data":    comments,
+		"data": map[string]interface{}{
+			"comments":    comments,
+			"total":       total,
+			"has_more":    hasMore,
+			"next_cursor": nextCursor,
+		},
 	})
 }
 
@@ -49,9 +143,9 @@ func AddComment(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
-	// 获取当前用户ID
-	userID, exists := ctx.Get("userID")
-	if !exists {
+	// 获取当前用户ID，未登录时按游客评论处理
+	userID, loggedIn := ctx.Get("userID")
+	if !loggedIn && !guestCommentsEnabled() {
 		ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
 			"code":    401,
 			"message": "请先登录",
@@ -63,6 +157,10 @@ func AddComment(c context.Context, ctx *app.RequestContext) {
 	type CommentRequest struct {
 		Content  string `json:"content"`
 		ParentID *uint  `json:"parent_id,omitempty"` // 可选的父评论ID
+		Honeypot string `json:"hp"`                  // 隐藏表单字段，正常用户应留空
+		Name     string `json:"name"`                // 游客昵称
+		Email    string `json:"email"`               // 游客邮箱，不公开显示
+		Website  string `json:"website"`             // 游客个人网站 (可选)
 	}
 	var req CommentRequest
 	if err := ctx.BindJSON(&req); err != nil {
@@ -75,6 +173,7 @@ func AddComment(c context.Context, ctx *app.RequestContext) {
 	}
 
 	// 验证评论内容
+	req.Content = strings.TrimSpace(req.Content)
 	if req.Content == "" {
 		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
 			"code":    400,
@@ -82,26 +181,42 @@ func AddComment(c context.Context, ctx *app.RequestContext) {
 		})
 		return
 	}
+	if utf8.RuneCountInString(req.Content) > maxCommentLength {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "评论内容不能超过 " + strconv.Itoa(maxCommentLength) + " 个字符",
+		})
+		return
+	}
 
-	// 检查用户角色
-	var user models.User
-	config.DB.First(&user, userID)
+	// 检查文章是否可以评论，以及回复的父评论是否有效
+	post, settings, ok := findCommentablePost(ctx, uint(postID), loggedIn)
+	if !ok {
+		return
+	}
+	if req.ParentID != nil && !checkCommentParent(ctx, post, *req.ParentID) {
+		return
+	}
 
 	// 创建评论
 	comment := models.Comment{
-		Content:  req.Content,
-		PostID:   uint(postID),
-		UserID:   uint(userID.(uint)),
-		ParentID: req.ParentID,
+		Content:     req.Content,
+		ContentHash: commentContentHash(req.Content),
+		PostID:      post.ID,
+		ParentID:    req.ParentID,
+		IP:          ctx.ClientIP(),
 	}
-
-	// 管理员发表的评论自动通过审核
-	if user.Role == "admin" {
-		comment.Status = "approved"
-	} else {
-		comment.Status = "pending" // 普通用户评论需要审核
+	var user models.User
+	if loggedIn {
+		config.DB.First(&user, userID)
+		comment.UserID = &user.ID
+	} else if !fillGuestInfo(ctx, &comment, req.Name, req.Email, req.Website) {
+		return
 	}
 
+	// 经垃圾评论检测和用户信任级别决定评论状态，游客评论始终需要审核
+	assessComment(&comment, user, settings, req.Honeypot)
+
 	// 保存评论
 	result := config.DB.Create(&comment)
 	if result.Error != nil {
@@ -112,13 +227,19 @@ func AddComment(c context.Context, ctx *app.RequestContext) {
 		})
 		return
 	}
+	if !loggedIn {
+		setGuestCookie(ctx, comment)
+	}
 
 	// 加载用户信息
-	config.DB.Preload("User").First(&comment, comment.ID)
+	config.DB.Scopes(publicCommentFields).Preload("User", publicUserFields).First(&comment, comment.ID)
+	comments := []models.Comment{comment}
+	fillCommentReactions(ctx, comments)
+	comment = comments[0]
 
 	// 返回评论信息
 	message := "评论已提交，等待审核"
-	if user.Role == "admin" {
+	if comment.Status == models.CommentStatusApproved {
 		message = "评论已发布"
 	}
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
@@ -128,63 +249,131 @@ func AddComment(c context.Context, ctx *app.RequestContext) {
 	})
 }
 
-// DeleteComment 删除评论 (需要权限检查: 评论作者或管理员)
-func DeleteComment(c context.Context, ctx *app.RequestContext) {
-	// 获取评论ID
-	commentIDStr := ctx.Param("id")
-	commentID, err := strconv.ParseUint(commentIDStr, 10, 64)
-	if err != nil {
-		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
-			"code":    400,
-			"message": "无效的评论ID",
-			"error":   err.Error(),
+// maxCommentLength 评论内容的最大字符数
+const maxCommentLength = 5000
+
+// findCommentablePost 查询可评论的文章：文章存在、已发布且按评论设置接受当前用户的评论，失败时直接写入错误响应
+func findCommentablePost(ctx *app.RequestContext, postID uint, loggedIn bool) (models.Post, models.CommentSettings, bool) {
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil || post.Status != models.PostStatusPublished {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "文章不存在或尚未发布",
 		})
-		return
+		return post, models.CommentSettings{}, false
 	}
 
-	// 获取当前用户ID和角色
-	userID, exists := ctx.Get("userID")
-	if !exists {
+	settings := resolveCommentSettings(post)
+	if settings.Policy == models.CommentPolicyClosed {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "该文章已关闭评论",
+		})
+		return post, settings, false
+	}
+	if !settings.Open {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "该文章的评论已于 " + settings.ClosesAt.Format("2006-01-02 15:04") + " 自动关闭",
+		})
+		return post, settings, false
+	}
+	if settings.Policy == models.CommentPolicyRegistered && !loggedIn {
 		ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
 			"code":    401,
-			"message": "请先登录",
+			"message": "该文章仅允许登录用户评论",
 		})
-		return
+		return post, settings, false
 	}
+	return post, settings, true
+}
 
-	// 查询评论
-	var comment models.Comment
-	result := config.DB.First(&comment, commentID)
-	if result.Error != nil {
-		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
-			"code":    404,
-			"message": "评论不存在",
-			"error":   result.Error.Error(),
+// checkCommentParent 检查回复的父评论存在、已通过审核、属于同一篇文章且未超过嵌套层数，失败时直接写入错误响应
+func checkCommentParent(ctx *app.RequestContext, post models.Post, parentID uint) bool {
+	var parent models.Comment
+	if err := config.DB.First(&parent, parentID).Error; err != nil {
+		ctx.JSON(consts.StatusUnprocessableEntity, map[string]interface{}{
+			"code":    422,
+			"message": "回复的评论不存在",
 		})
-		return
+		return false
+	}
+	if parent.PostID != post.ID {
+		ctx.JSON(consts.StatusUnprocessableEntity, map[string]interface{}{
+			"code":    422,
+			"message": "回复的评论不属于该文章",
+		})
+		return false
+	}
+	if parent.IsDeleted {
+		ctx.JSON(consts.StatusUnprocessableEntity, map[string]interface{}{
+			"code":    422,
+			"message": "不能回复已删除的评论",
+		})
+		return false
+	}
+	if parent.Status != models.CommentStatusApproved {
+		ctx.JSON(consts.StatusUnprocessableEntity, map[string]interface{}{
+			"code":    422,
+			"message": "不能回复尚未通过审核的评论",
+		})
+		return false
 	}
 
-	// 检查权限（只有评论作者或管理员可以删除评论）
-	if comment.UserID != uint(userID.(uint)) {
-		// 检查是否为管理员
-		var user models.User
-		config.DB.First(&user, userID)
-		if user.Role != "admin" {
-			ctx.JSON(consts.StatusForbidden, map[string]interface{}{
-				"code":    403,
-				"message": "无权删除该评论",
-			})
-			return
+	// 父评论所在层数 (顶层为第1层)，新回复位于其下一层
+	depth := commentDepth(parent.ParentID, maxCommentDepth(), func(id uint) (*uint, error) {
+		var ancestor models.Comment
+		err := config.DB.Unscoped().Select("id, parent_id").First(&ancestor, id).Error
+		return ancestor.ParentID, err
+	})
+	if depth >= maxCommentDepth() {
+		ctx.JSON(consts.StatusUnprocessableEntity, map[string]interface{}{
+			"code":    422,
+			"message": "回复层级过深，最多允许 " + strconv.Itoa(maxCommentDepth()) + " 层",
+		})
+		return false
+	}
+	return true
+}
+
+// commentDepth 根据父评论ID逐级向上查找，计算评论所在层数 (顶层为第1层)，超过 limit 层或查找失败时停止，
+// parentOf 返回指定评论的父评论ID
+func commentDepth(parentID *uint, limit int, parentOf func(id uint) (*uint, error)) int {
+	depth := 1
+	for parentID != nil && depth <= limit {
+		next, err := parentOf(*parentID)
+		if err != nil {
+			break
 		}
+		parentID = next
+		depth++
 	}
+	return depth
+}
 
-	// 删除评论
-	result = config.DB.Delete(&comment)
-	if result.Error != nil {
+// DeleteComment 删除评论 (评论作者、持有编辑凭证的游客或管理员)
+func DeleteComment(c context.Context, ctx *app.RequestContext) {
+	comment, _, ok := findOwnComment(ctx)
+	if !ok {
+		return
+	}
+
+	if comment.IsDeleted {
+		ctx.JSON(consts.StatusGone, map[string]interface{}{
+			"code":    410,
+			"message": "评论已被删除",
+		})
+		return
+	}
+
+	// 删除评论，仍有回复时保留为占位
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return removeComment(tx, &comment)
+	}); err != nil {
 		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
 			"code":    500,
 			"message": "删除评论失败",
-			"error":   result.Error.Error(),
+			"error":   err.Error(),
 		})
 		return
 	}
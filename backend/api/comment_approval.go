@@ -2,38 +2,188 @@ package api
 
 import (
 	"context"
+	"errors"
 	"strconv"
+	"time"
 
 	"github.com/alvinhmg/blog/config"
 	"github.com/alvinhmg/blog/models"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
 )
 
+// maxBulkComments 单次批量审核的评论数上限
+const maxBulkComments = 500
+
+// BulkCommentRequest 批量审核评论请求
+type BulkCommentRequest struct {
+	CommentIDs []uint `json:"comment_ids"`
+	Action     string `json:"action"` // approve, reject, spam, restore
+}
+
+// CommentBulkResult 单条评论的批量审核结果
+type CommentBulkResult struct {
+	CommentID uint   `json:"comment_id"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+// commentActionStatus 审核操作对应的目标状态，restore 将被拒绝或标记为垃圾的评论放回待审核队列
+var commentActionStatus = map[string]string{
+	"approve": models.CommentStatusApproved,
+	"reject":  models.CommentStatusRejected,
+	"spam":    models.CommentStatusSpam,
+	"restore": models.CommentStatusPending,
+}
+
+// GetModerationQueue 获取评论审核队列，支持按状态、文章、用户、日期和垃圾评论分数过滤
+func GetModerationQueue(c context.Context, ctx *app.RequestContext) {
+	listComments(ctx, ctx.DefaultQuery("status", models.CommentStatusPending))
+}
+
 // GetPendingComments 获取待审核评论列表
 func GetPendingComments(c context.Context, ctx *app.RequestContext) {
+	listComments(ctx, models.CommentStatusPending)
+}
+
+// listComments 按过滤条件分页查询评论，status 为 all 时不按状态过滤
+func listComments(ctx *app.RequestContext, status string) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	db := config.DB.Model(&models.Comment{})
+	if status != "all" {
+		if !models.IsValidCommentStatus(status) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的评论状态: " + status,
+			})
+			return
+		}
+		db = db.Where("status = ?", status)
+	}
+	if postID := ctx.Query("post_id"); postID != "" {
+		db = db.Where("post_id = ?", postID)
+	}
+	if userID := ctx.Query("user_id"); userID != "" {
+		db = db.Where("user_id = ?", userID)
+	}
+	if minScore := ctx.Query("min_score"); minScore != "" {
+		db = db.Where("spam_score >= ?", minScore)
+	}
+	// 日期范围 (YYYY-MM-DD)，结束日期包含当天
+	if from := ctx.Query("from"); from != "" {
+		date, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的开始日期，格式应为 YYYY-MM-DD",
+			})
+			return
+		}
+		db = db.Where("created_at >= ?", date)
+	}
+	if to := ctx.Query("to"); to != "" {
+		date, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的结束日期，格式应为 YYYY-MM-DD",
+			})
+			return
+		}
+		db = db.Where("created_at < ?", date.AddDate(0, 0, 1))
+	}
+
+	var total int64
+	db.Count(&total)
+
+	order := "created_at DESC"
+	switch ctx.Query("order") {
+	case "asc":
+		order = "created_at ASC"
+	case "score":
+		order = "spam_score DESC, created_at DESC"
+	}
+
 	var comments []models.Comment
-	if err := config.DB.Where("status = ?", "pending").Preload("User").Preload("Post").Find(&comments).Error; err != nil {
+	if err := db.Preload("User").Order(order).Limit(pageSize).Offset((page - 1) * pageSize).Find(&comments).Error; err != nil {
 		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
 			"code":    500,
-			"message": "查询待审核评论失败",
+			"message": "查询评论失败",
 			"error":   err.Error(),
 		})
 		return
 	}
+	fillCommentPostInfo(comments)
 
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
-		"code":    0,
-		"message": "获取待审核评论成功",
-		"data":    comments,
+		"code":    200,
+		"message": "获取评论列表成功",
+		"data": map[string]interface{}{
+			"comments":   comments,
+			"total":      total,
+			"page":       page,
+			"page_size":  pageSize,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
 	})
 }
 
+// fillCommentPostInfo 批量填充评论所属文章的摘要信息
+func fillCommentPostInfo(comments []models.Comment) {
+	if len(comments) == 0 {
+		return
+	}
+
+	postIDs := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		postIDs = append(postIDs, comment.PostID)
+	}
+
+	var posts []models.PostSummary
+	config.DB.Unscoped().Model(&models.Post{}).Select("id, title, slug").Where("id IN ?", postIDs).Scan(&posts)
+	postMap := make(map[uint]models.PostSummary, len(posts))
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+	for i := range comments {
+		if post, ok := postMap[comments[i].PostID]; ok {
+			comments[i].PostInfo = &post
+		}
+	}
+}
+
 // ApproveComment 批准评论
 func ApproveComment(c context.Context, ctx *app.RequestContext) {
-	// 获取评论ID
-	commentIDStr := ctx.Param("id")
-	commentID, err := strconv.ParseUint(commentIDStr, 10, 64)
+	moderateComment(ctx, "approve", "批准评论成功")
+}
+
+// RejectComment 拒绝评论
+func RejectComment(c context.Context, ctx *app.RequestContext) {
+	moderateComment(ctx, "reject", "已拒绝评论")
+}
+
+// MarkCommentSpam 将评论标记为垃圾评论
+func MarkCommentSpam(c context.Context, ctx *app.RequestContext) {
+	moderateComment(ctx, "spam", "已标记为垃圾评论")
+}
+
+// RestoreComment 将被拒绝或标记为垃圾的评论恢复到待审核状态
+func RestoreComment(c context.Context, ctx *app.RequestContext) {
+	moderateComment(ctx, "restore", "评论已恢复到待审核状态")
+}
+
+// moderateComment 对单条评论执行审核操作
+func moderateComment(ctx *app.RequestContext, action string, message string) {
+	commentID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
 			"code":    400,
@@ -42,8 +192,8 @@ func ApproveComment(c context.Context, ctx *app.RequestContext) {
 		})
 		return
 	}
+	userID, _ := ctx.Get("userID")
 
-	// 查询评论
 	var comment models.Comment
 	if err := config.DB.First(&comment, commentID).Error; err != nil {
 		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
@@ -53,19 +203,118 @@ func ApproveComment(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
-	// 更新评论状态
-	if err := config.DB.Model(&comment).Update("status", "approved").Error; err != nil {
+	if err := applyCommentAction(config.DB, &comment, action, userID.(uint)); err != nil {
+		if errors.Is(err, errInvalidCommentTransition) {
+			ctx.JSON(consts.StatusConflict, map[string]interface{}{
+				"code":    409,
+				"message": err.Error() + ": " + comment.Status,
+			})
+			return
+		}
 		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
 			"code":    500,
-			"message": "批准评论失败",
+			"message": "审核评论失败",
 			"error":   err.Error(),
 		})
 		return
 	}
 
+	config.DB.Preload("User").First(&comment, comment.ID)
+
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
-		"code":    0,
-		"message": "批准评论成功",
+		"code":    200,
+		"message": message,
 		"data":    comment,
 	})
 }
+
+// errInvalidCommentTransition 当前状态下不允许执行该审核操作
+var errInvalidCommentTransition = errors.New("当前评论状态不允许该操作")
+
+// applyCommentAction 按状态机修改评论状态并记录审核人，审核后评论不再处于举报自动隐藏状态
+func applyCommentAction(tx *gorm.DB, comment *models.Comment, action string, moderatorID uint) error {
+	status, ok := commentActionStatus[action]
+	if !ok {
+		return errors.New("不支持的审核操作: " + action)
+	}
+	if !models.CanTransitionCommentStatus(comment.Status, status) {
+		return errInvalidCommentTransition
+	}
+
+	now := time.Now()
+	return tx.Model(comment).Updates(map[string]interface{}{
+		"status":          status,
+		"moderated_by_id": moderatorID,
+		"moderated_at":    now,
+		"auto_hidden":     false,
+	}).Error
+}
+
+// BulkModerateComments 批量审核评论，逐条执行并返回每条评论的结果
+func BulkModerateComments(c context.Context, ctx *app.RequestContext) {
+	var req BulkCommentRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	req.CommentIDs = uniqueIDs(req.CommentIDs)
+	if len(req.CommentIDs) == 0 || len(req.CommentIDs) > maxBulkComments {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "comment_ids 不能为空且单次最多操作 500 条评论",
+		})
+		return
+	}
+	if _, ok := commentActionStatus[req.Action]; !ok {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "不支持的审核操作: " + req.Action,
+		})
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	var comments []models.Comment
+	if err := config.DB.Where("id IN ?", req.CommentIDs).Find(&comments).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "查询评论失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	commentMap := make(map[uint]models.Comment, len(comments))
+	for _, comment := range comments {
+		commentMap[comment.ID] = comment
+	}
+
+	results := make([]CommentBulkResult, 0, len(req.CommentIDs))
+	succeeded := 0
+	for _, commentID := range req.CommentIDs {
+		result := CommentBulkResult{CommentID: commentID}
+		comment, ok := commentMap[commentID]
+		if !ok {
+			result.Error = "评论不存在"
+		} else if err := applyCommentAction(config.DB, &comment, req.Action, userID.(uint)); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			succeeded++
+		}
+		results = append(results, result)
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "批量审核完成",
+		"data": map[string]interface{}{
+			"results":   results,
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+		},
+	})
+}
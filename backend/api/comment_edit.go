@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// UpdateCommentRequest 编辑评论请求
+type UpdateCommentRequest struct {
+	Content string `json:"content"`
+}
+
+// commentEditWindow 评论发表后允许作者编辑的时长
+func commentEditWindow() time.Duration {
+	return config.GetEnvDuration("COMMENT_EDIT_WINDOW", 15*time.Minute)
+}
+
+// findOwnComment 查询评论并检查当前用户是否为作者或管理员，未登录时检查是否持有该游客评论的编辑凭证，
+// 失败时直接写入错误响应
+func findOwnComment(ctx *app.RequestContext) (models.Comment, models.User, bool) {
+	var comment models.Comment
+	var user models.User
+
+	commentID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的评论ID",
+		})
+		return comment, user, false
+	}
+	if err := config.DB.First(&comment, commentID).Error; err != nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "评论不存在",
+		})
+		return comment, user, false
+	}
+
+	userID, loggedIn := ctx.Get("userID")
+	if !loggedIn {
+		if comment.UserID == nil && verifyGuestCookie(ctx, comment.ID) {
+			return comment, user, true
+		}
+		ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
+			"code":    401,
+			"message": "请先登录",
+		})
+		return comment, user, false
+	}
+	config.DB.First(&user, userID)
+	if (comment.UserID == nil || *comment.UserID != user.ID) && user.Role != "admin" {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "无权操作该评论",
+		})
+		return comment, user, false
+	}
+	return comment, user, true
+}
+
+// UpdateComment 编辑评论：作者只能在发表后的限定时间内编辑，编辑前的内容保存到编辑历史，
+// 待审核或已通过的评论编辑后重新经过垃圾评论检测和审核，已拒绝、垃圾或被自动隐藏的评论不能编辑
+func UpdateComment(c context.Context, ctx *app.RequestContext) {
+	comment, user, ok := findOwnComment(ctx)
+	if !ok {
+		return
+	}
+	if comment.IsDeleted {
+		ctx.JSON(consts.StatusGone, map[string]interface{}{
+			"code":    410,
+			"message": "评论已被删除",
+		})
+		return
+	}
+	// 已被拒绝、标记为垃圾或因举报被自动隐藏的评论不能编辑，避免通过编辑绕过审核
+	if (comment.Status != models.CommentStatusPending && comment.Status != models.CommentStatusApproved) || comment.AutoHidden {
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": "评论当前状态不允许编辑: " + comment.Status,
+		})
+		return
+	}
+	if user.Role != "admin" && time.Since(comment.CreatedAt) > commentEditWindow() {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "评论发表超过 " + commentEditWindow().String() + " 后不能再编辑",
+		})
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "评论内容不能为空",
+		})
+		return
+	}
+	if utf8.RuneCountInString(req.Content) > maxCommentLength {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "评论内容不能超过 " + strconv.Itoa(maxCommentLength) + " 个字符",
+		})
+		return
+	}
+	if req.Content == comment.Content {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "评论内容没有变化",
+		})
+		return
+	}
+
+	// 管理员编辑时不改变审核状态，作者编辑时按作者的信任级别重新判定，游客编辑后重新进入审核
+	revision := models.CommentRevision{
+		CommentID: comment.ID,
+		UserID:    user.ID,
+		Content:   comment.Content,
+	}
+	comment.Content = req.Content
+	comment.ContentHash = commentContentHash(req.Content)
+	if user.Role != "admin" {
+		var post models.Post
+		config.DB.First(&post, comment.PostID)
+		previous := comment.Status
+		assessComment(&comment, user, resolveCommentSettings(post), "")
+		// 重新判定只会让审核更严格，待审核的评论不会因编辑而直接通过
+		if previous == models.CommentStatusPending && comment.Status == models.CommentStatusApproved {
+			comment.Status = models.CommentStatusPending
+		}
+	}
+
+	now := time.Now()
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		return tx.Model(&comment).Updates(map[string]interface{}{
+			"content":      comment.Content,
+			"content_hash": comment.ContentHash,
+			"status":       comment.Status,
+			"spam_score":   comment.SpamScore,
+			"spam_reasons": comment.SpamReasons,
+			"edited_at":    now,
+		}).Error
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "编辑评论失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.Scopes(publicCommentFields).Preload("User", publicUserFields).First(&comment, comment.ID)
+	comments := []models.Comment{comment}
+	fillCommentReactions(ctx, comments)
+	comment = comments[0]
+
+	message := "评论已更新"
+	if comment.Status != models.CommentStatusApproved {
+		message = "评论已更新，等待审核"
+	}
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": message,
+		"data":    comment,
+	})
+}
+
+// GetCommentRevisions 获取评论的编辑历史 (评论作者或管理员)
+func GetCommentRevisions(c context.Context, ctx *app.RequestContext) {
+	comment, _, ok := findOwnComment(ctx)
+	if !ok {
+		return
+	}
+
+	var revisions []models.CommentRevision
+	if err := config.DB.Where("comment_id = ?", comment.ID).Order("created_at DESC").Find(&revisions).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取编辑历史失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取编辑历史成功",
+		"data":    revisions,
+	})
+}
+
+// removeComment 删除评论及其编辑历史和反应，并结案针对该评论的待处理举报：仍有回复的评论清空内容后保留为占位，
+// 以维持回复结构，否则直接删除，并依次清理因此不再有回复的占位父评论
+func removeComment(tx *gorm.DB, comment *models.Comment) error {
+	if err := tx.Where("comment_id = ?", comment.ID).Delete(&models.CommentRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("comment_id = ?", comment.ID).Delete(&models.CommentReaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.CommentReport{}).
+		Where("comment_id = ? AND status = ?", comment.ID, models.CommentReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":      models.CommentReportStatusResolved,
+			"resolution":  "deleted",
+			"resolved_at": time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+
+	var replies int64
+	tx.Model(&models.Comment{}).Where("parent_id = ?", comment.ID).Count(&replies)
+	if replies > 0 {
+		return tx.Model(comment).Updates(map[string]interface{}{
+			"is_deleted":   true,
+			"content":      "",
+			"content_hash": "",
+			"score":        0,
+		}).Error
+	}
+	if err := tx.Delete(comment).Error; err != nil {
+		return err
+	}
+
+	for parentID := comment.ParentID; parentID != nil; {
+		var parent models.Comment
+		if err := tx.First(&parent, *parentID).Error; err != nil || !parent.IsDeleted {
+			return nil
+		}
+		tx.Model(&models.Comment{}).Where("parent_id = ?", parent.ID).Count(&replies)
+		if replies > 0 {
+			return nil
+		}
+		if err := tx.Delete(&parent).Error; err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// guestCommentsEnabled 是否允许未登录的游客发表评论 (COMMENT_ALLOW_GUESTS)
+func guestCommentsEnabled() bool {
+	enabled, _ := strconv.ParseBool(config.GetEnv("COMMENT_ALLOW_GUESTS", "false"))
+	return enabled
+}
+
+// guestEditWindow 游客发表评论后凭 Cookie 编辑评论的时长
+func guestEditWindow() time.Duration {
+	return config.GetEnvDuration("COMMENT_GUEST_EDIT_WINDOW", 10*time.Minute)
+}
+
+// guestAvatarHash 按 Gravatar 规则计算邮箱哈希 (去除首尾空白并转为小写后取 SHA-256)
+func guestAvatarHash(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// fillGuestInfo 校验游客的昵称、邮箱和网站并写入评论，失败时直接写入错误响应
+func fillGuestInfo(ctx *app.RequestContext, comment *models.Comment, name, email, website string) bool {
+	name = strings.TrimSpace(name)
+	email = strings.TrimSpace(email)
+	website = strings.TrimSpace(website)
+
+	if name == "" || utf8.RuneCountInString(name) > 50 {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "游客评论需要填写昵称，且不能超过 50 个字符",
+		})
+		return false
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email || len(email) > 100 {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "游客评论需要填写有效的邮箱",
+		})
+		return false
+	}
+	if website != "" {
+		u, err := url.Parse(website)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(website) > 255 {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "个人网站必须是以 http:// 或 https:// 开头的有效链接",
+			})
+			return false
+		}
+	}
+
+	comment.GuestName = name
+	comment.GuestEmail = email
+	comment.GuestWebsite = website
+	comment.AvatarHash = guestAvatarHash(email)
+	return true
+}
+
+// guestCookieName 游客评论编辑凭证的 Cookie 名称
+func guestCookieName(commentID uint) string {
+	return "guest_comment_" + strconv.FormatUint(uint64(commentID), 10)
+}
+
+// signGuestToken 用服务端密钥对评论ID和过期时间签名，格式为 "过期时间戳.签名"
+func signGuestToken(commentID uint, expires int64) string {
+	mac := hmac.New(sha256.New, []byte("guest-comment:"+config.GetEnv("JWT_SECRET", "")))
+	mac.Write([]byte(strconv.FormatUint(uint64(commentID), 10) + ":" + strconv.FormatInt(expires, 10)))
+	return strconv.FormatInt(expires, 10) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// setGuestCookie 下发游客编辑评论的签名 Cookie，站点地址为 https 时只通过 HTTPS 传输
+func setGuestCookie(ctx *app.RequestContext, comment models.Comment) {
+	window := guestEditWindow()
+	token := signGuestToken(comment.ID, comment.CreatedAt.Add(window).Unix())
+	secure := strings.HasPrefix(strings.ToLower(siteURL()), "https://")
+	ctx.SetCookie(guestCookieName(comment.ID), token, int(window.Seconds()), "/api/comments", "", protocol.CookieSameSiteLaxMode, secure, true)
+}
+
+// verifyGuestCookie 检查请求携带的 Cookie 签名有效且未过期
+func verifyGuestCookie(ctx *app.RequestContext, commentID uint) bool {
+	token := string(ctx.Cookie(guestCookieName(commentID)))
+	raw, _, found := strings.Cut(token, ".")
+	if !found {
+		return false
+	}
+	expires, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(signGuestToken(commentID, expires)))
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// CommentSettingsRequest 文章评论设置请求，字段为空时不修改
+type CommentSettingsRequest struct {
+	Policy        *string `json:"policy"`          // inherit, open, closed, registered
+	AutoCloseDays *int    `json:"auto_close_days"` // 发布后多少天自动关闭评论，0 表示不自动关闭，-1 表示跟随站点设置
+	Moderation    *string `json:"moderation"`      // inherit, required, auto
+}
+
+// siteCommentPolicy 站点默认评论策略
+func siteCommentPolicy() string {
+	policy := config.GetEnv("COMMENT_POLICY", models.CommentPolicyOpen)
+	if !models.IsValidCommentPolicy(policy) || policy == models.CommentPolicyInherit {
+		return models.CommentPolicyOpen
+	}
+	return policy
+}
+
+// siteCommentModeration 站点默认评论审核方式
+func siteCommentModeration() string {
+	moderation := config.GetEnv("COMMENT_MODERATION", models.CommentModerationRequired)
+	if !models.IsValidCommentModeration(moderation) || moderation == models.CommentModerationInherit {
+		return models.CommentModerationRequired
+	}
+	return moderation
+}
+
+// resolveCommentSettings 合并文章与站点设置，计算文章实际生效的评论设置
+func resolveCommentSettings(post models.Post) models.CommentSettings {
+	settings := models.CommentSettings{
+		Policy:        post.CommentPolicy,
+		Moderation:    post.CommentModeration,
+		AutoCloseDays: config.GetEnvInt("COMMENT_AUTO_CLOSE_DAYS", 0),
+	}
+	if settings.Policy == "" || settings.Policy == models.CommentPolicyInherit {
+		settings.Policy = siteCommentPolicy()
+	}
+	if settings.Moderation == "" || settings.Moderation == models.CommentModerationInherit {
+		settings.Moderation = siteCommentModeration()
+	}
+	if post.CommentAutoCloseDays != nil {
+		settings.AutoCloseDays = *post.CommentAutoCloseDays
+	}
+
+	// 自动关闭时间从首次发布开始计算
+	publishedAt := post.PublishedAt
+	if publishedAt == nil && post.Status == models.PostStatusPublished {
+		publishedAt = &post.CreatedAt
+	}
+	if settings.AutoCloseDays > 0 && publishedAt != nil {
+		closesAt := publishedAt.AddDate(0, 0, settings.AutoCloseDays)
+		settings.ClosesAt = &closesAt
+	}
+
+	settings.Open = post.Status == models.PostStatusPublished &&
+		settings.Policy != models.CommentPolicyClosed &&
+		(settings.ClosesAt == nil || time.Now().Before(*settings.ClosesAt))
+	return settings
+}
+
+// UpdatePostCommentSettings 修改文章的评论设置
+func UpdatePostCommentSettings(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req CommentSettingsRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Policy != nil {
+		if !models.IsValidCommentPolicy(*req.Policy) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的评论策略: " + *req.Policy,
+			})
+			return
+		}
+		updates["comment_policy"] = *req.Policy
+	}
+	if req.Moderation != nil {
+		if !models.IsValidCommentModeration(*req.Moderation) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的评论审核方式: " + *req.Moderation,
+			})
+			return
+		}
+		updates["comment_moderation"] = *req.Moderation
+	}
+	if req.AutoCloseDays != nil {
+		switch {
+		case *req.AutoCloseDays == -1:
+			updates["comment_auto_close_days"] = nil
+		case *req.AutoCloseDays >= 0:
+			updates["comment_auto_close_days"] = *req.AutoCloseDays
+		default:
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "auto_close_days 必须大于等于0，或为 -1 表示跟随站点设置",
+			})
+			return
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := config.DB.Model(&post).Updates(updates).Error; err != nil {
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "更新评论设置失败",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	config.DB.First(&post, post.ID)
+	settings := resolveCommentSettings(post)
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "更新评论设置成功",
+		"data": map[string]interface{}{
+			"comment_policy":          post.CommentPolicy,
+			"comment_auto_close_days": post.CommentAutoCloseDays,
+			"comment_moderation":      post.CommentModeration,
+			"comment_settings":        settings,
+		},
+	})
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddCommentReaction 对评论添加反应 (赞同或表情，同一种反应每个用户只记录一次)
+func AddCommentReaction(c context.Context, ctx *app.RequestContext) {
+	setCommentReaction(ctx, true)
+}
+
+// RemoveCommentReaction 取消对评论的反应
+func RemoveCommentReaction(c context.Context, ctx *app.RequestContext) {
+	setCommentReaction(ctx, false)
+}
+
+// setCommentReaction 记录或删除当前用户的反应，并根据反应记录重新计算评论得分
+func setCommentReaction(ctx *app.RequestContext, added bool) {
+	commentID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的评论ID",
+		})
+		return
+	}
+	reactionType := ctx.Param("type")
+	if !models.IsValidCommentReaction(reactionType) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "不支持的反应类型: " + reactionType,
+		})
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	// 只能对公开显示的评论添加反应
+	var comment models.Comment
+	if err := config.DB.First(&comment, commentID).Error; err != nil || comment.Status != models.CommentStatusApproved || comment.IsDeleted {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "评论不存在",
+		})
+		return
+	}
+
+	reaction := models.CommentReaction{UserID: userID.(uint), CommentID: comment.ID, Type: reactionType}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if added {
+			// 唯一索引冲突时忽略，保证重复操作不会重复计数
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Where("user_id = ? AND comment_id = ? AND type = ?", reaction.UserID, reaction.CommentID, reaction.Type).
+				Delete(&models.CommentReaction{}).Error; err != nil {
+				return err
+			}
+		}
+		// 以赞同记录为准同步评论得分
+		return tx.Model(&comment).UpdateColumn("score",
+			tx.Model(&models.CommentReaction{}).Select("COUNT(*)").
+				Where("comment_id = ? AND type = ?", comment.ID, models.CommentReactionUpvote)).Error
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "更新评论反应失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.Select("id, score").First(&comment, comment.ID)
+	comments := []models.Comment{comment}
+	fillCommentReactions(ctx, comments)
+
+	message := "已添加反应"
+	if !added {
+		message = "已取消反应"
+	}
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": message,
+		"data": map[string]interface{}{
+			"score":        comments[0].Score,
+			"reactions":    comments[0].Reactions,
+			"my_reactions": comments[0].MyReactions,
+		},
+	})
+}
+
+// fillCommentReactions 批量填充评论树中每条评论的各类反应数量，已登录时同时标记当前用户的反应
+func fillCommentReactions(ctx *app.RequestContext, roots []models.Comment) {
+	var all []*models.Comment
+	var collect func(comments []models.Comment)
+	collect = func(comments []models.Comment) {
+		for i := range comments {
+			all = append(all, &comments[i])
+			collect(comments[i].Replies)
+		}
+	}
+	collect(roots)
+	if len(all) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(all))
+	for _, comment := range all {
+		ids = append(ids, comment.ID)
+	}
+
+	var counts []struct {
+		CommentID uint
+		Type      string
+		Count     int
+	}
+	config.DB.Model(&models.CommentReaction{}).
+		Select("comment_id, type, COUNT(*) AS count").
+		Where("comment_id IN ?", ids).
+		Group("comment_id, type").
+		Scan(&counts)
+
+	reactions := make(map[uint]map[string]int, len(all))
+	for _, count := range counts {
+		if reactions[count.CommentID] == nil {
+			reactions[count.CommentID] = make(map[string]int)
+		}
+		reactions[count.CommentID][count.Type] = count.Count
+	}
+
+	mine := make(map[uint][]string)
+	if userID, exists := ctx.Get("userID"); exists {
+		var own []models.CommentReaction
+		config.DB.Select("comment_id, type").Where("user_id = ? AND comment_id IN ?", userID, ids).Find(&own)
+		for _, reaction := range own {
+			mine[reaction.CommentID] = append(mine[reaction.CommentID], reaction.Type)
+		}
+	}
+
+	for _, comment := range all {
+		comment.Reactions = reactions[comment.ID]
+		comment.MyReactions = mine[comment.ID]
+	}
+}
@@ -0,0 +1,327 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentReportRequest 举报评论请求
+type CommentReportRequest struct {
+	Reason string `json:"reason"` // spam, abuse, harassment, off_topic, other
+	Detail string `json:"detail"` // 举报说明，原因为 other 时必填
+}
+
+// ResolveCommentReportRequest 处理评论举报请求
+type ResolveCommentReportRequest struct {
+	Action string `json:"action"` // reject, spam, dismiss
+}
+
+// errCommentAlreadyReported 同一用户重复举报同一条评论
+var errCommentAlreadyReported = errors.New("你已经举报过该评论")
+
+// commentReportThreshold 评论收到多少条待处理举报后自动隐藏 (0 表示关闭)
+func commentReportThreshold() int {
+	return config.GetEnvInt("COMMENT_REPORT_THRESHOLD", 3)
+}
+
+// ReportComment 举报评论：同一用户对同一评论只能举报一次，待处理举报数达到阈值时评论自动隐藏并回到审核队列
+func ReportComment(c context.Context, ctx *app.RequestContext) {
+	commentID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的评论ID",
+		})
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	var req CommentReportRequest
+	if err := ctx.BindAndValidate(&req); err != nil || !models.IsValidCommentReportReason(req.Reason) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误，reason 可选 spam、abuse、harassment、off_topic、other",
+		})
+		return
+	}
+	req.Detail = strings.TrimSpace(req.Detail)
+	if req.Reason == models.CommentReportReasonOther && req.Detail == "" {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "举报原因为其他时需要填写说明",
+		})
+		return
+	}
+	if utf8.RuneCountInString(req.Detail) > 500 {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "举报说明不能超过 500 个字符",
+		})
+		return
+	}
+
+	// 只能举报公开显示的评论
+	var comment models.Comment
+	if err := config.DB.First(&comment, commentID).Error; err != nil || comment.Status != models.CommentStatusApproved || comment.IsDeleted {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "评论不存在",
+		})
+		return
+	}
+	if comment.UserID != nil && *comment.UserID == userID.(uint) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "不能举报自己的评论",
+		})
+		return
+	}
+
+	var existing int64
+	config.DB.Model(&models.CommentReport{}).Where("comment_id = ? AND user_id = ?", comment.ID, userID).Count(&existing)
+	if existing > 0 {
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": errCommentAlreadyReported.Error(),
+		})
+		return
+	}
+
+	report := models.CommentReport{
+		CommentID: comment.ID,
+		UserID:    userID.(uint),
+		Reason:    req.Reason,
+		Detail:    req.Detail,
+		Status:    models.CommentReportStatusOpen,
+	}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// 并发的重复举报由唯一索引拦截
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errCommentAlreadyReported
+		}
+
+		// 待处理举报数达到阈值时隐藏评论，等待管理员处理举报
+		threshold := commentReportThreshold()
+		if threshold <= 0 {
+			return nil
+		}
+		var open int64
+		tx.Model(&models.CommentReport{}).
+			Where("comment_id = ? AND status = ?", comment.ID, models.CommentReportStatusOpen).
+			Count(&open)
+		if open < int64(threshold) {
+			return nil
+		}
+		return tx.Model(&comment).Updates(map[string]interface{}{
+			"status":      models.CommentStatusPending,
+			"auto_hidden": true,
+		}).Error
+	})
+	if errors.Is(err, errCommentAlreadyReported) {
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "举报评论失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "举报已提交，感谢你的反馈",
+		"data":    report,
+	})
+}
+
+// GetCommentReports 获取评论举报队列，支持按处理状态、原因和评论过滤，默认按举报时间先后排列
+func GetCommentReports(c context.Context, ctx *app.RequestContext) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	db := config.DB.Model(&models.CommentReport{})
+	if status := ctx.DefaultQuery("status", models.CommentReportStatusOpen); status != "all" {
+		if !models.IsValidCommentReportStatus(status) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的举报状态: " + status,
+			})
+			return
+		}
+		db = db.Where("status = ?", status)
+	}
+	if reason := ctx.Query("reason"); reason != "" {
+		db = db.Where("reason = ?", reason)
+	}
+	if commentID := ctx.Query("comment_id"); commentID != "" {
+		db = db.Where("comment_id = ?", commentID)
+	}
+
+	var total int64
+	db.Count(&total)
+
+	order := "created_at ASC"
+	if ctx.Query("order") == "desc" {
+		order = "created_at DESC"
+	}
+
+	var reports []models.CommentReport
+	err = db.Preload("User", publicUserFields).
+		Preload("Comment", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Comment.User", publicUserFields).
+		Order(order).Limit(pageSize).Offset((page - 1) * pageSize).
+		Find(&reports).Error
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "查询评论举报失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取评论举报列表成功",
+		"data": map[string]interface{}{
+			"reports":    reports,
+			"total":      total,
+			"page":       page,
+			"page_size":  pageSize,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// ResolveCommentReport 处理评论举报：reject/spam 按审核操作修改评论状态，dismiss 驳回举报并恢复被自动隐藏的评论，
+// 该评论所有待处理的举报一并结案
+func ResolveCommentReport(c context.Context, ctx *app.RequestContext) {
+	reportID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的举报ID",
+		})
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	var req ResolveCommentReportRequest
+	if err := ctx.BindAndValidate(&req); err != nil || (req.Action != "reject" && req.Action != "spam" && req.Action != "dismiss") {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误，action 可选 reject、spam、dismiss",
+		})
+		return
+	}
+
+	var report models.CommentReport
+	if err := config.DB.First(&report, reportID).Error; err != nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "举报不存在",
+		})
+		return
+	}
+	if report.Status != models.CommentReportStatusOpen {
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": "该举报已处理",
+		})
+		return
+	}
+
+	var comment models.Comment
+	if err := config.DB.Unscoped().First(&comment, report.CommentID).Error; err != nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "被举报的评论不存在",
+		})
+		return
+	}
+
+	status := models.CommentReportStatusResolved
+	if req.Action == "dismiss" {
+		status = models.CommentReportStatusDismissed
+	}
+	var resolved int64
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// 评论已被审核为目标状态时只结案举报
+		switch {
+		case req.Action != "dismiss" && comment.Status != commentActionStatus[req.Action]:
+			if err := applyCommentAction(tx, &comment, req.Action, userID.(uint)); err != nil {
+				return err
+			}
+		case comment.AutoHidden:
+			if err := applyCommentAction(tx, &comment, "approve", userID.(uint)); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.CommentReport{}).
+			Where("comment_id = ? AND status = ?", comment.ID, models.CommentReportStatusOpen).
+			Updates(map[string]interface{}{
+				"status":         status,
+				"resolution":     req.Action,
+				"resolved_by_id": userID,
+				"resolved_at":    now,
+			})
+		resolved = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidCommentTransition) {
+			ctx.JSON(consts.StatusConflict, map[string]interface{}{
+				"code":    409,
+				"message": err.Error() + ": " + comment.Status,
+			})
+			return
+		}
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "处理评论举报失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.Unscoped().Select("id, status").First(&comment, comment.ID)
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "举报已处理",
+		"data": map[string]interface{}{
+			"comment_id":     comment.ID,
+			"comment_status": comment.Status,
+			"resolved":       resolved,
+		},
+	})
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+)
+
+// commentLinkPattern 匹配评论中的链接
+var commentLinkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s<>"'()]+`)
+
+// SpamInput 垃圾评论检测的输入
+type SpamInput struct {
+	Content  string
+	PostID   uint
+	UserID   uint
+	IP       string
+	Honeypot string // 隐藏表单字段，正常用户不会填写
+	Links    []string
+}
+
+// SpamRule 垃圾评论检测规则，返回加分和命中原因，未命中时返回 0
+type SpamRule func(input *SpamInput) (int, string)
+
+// spamRule 已注册的检测规则
+type spamRule struct {
+	name  string
+	check SpamRule
+}
+
+// spamRules 按顺序执行的检测规则，可通过 RegisterSpamRule 扩展
+var spamRules = []spamRule{
+	{"honeypot", checkSpamHoneypot},
+	{"links", checkSpamLinks},
+	{"blocklist_words", checkSpamWords},
+	{"blocklist_domains", checkSpamDomains},
+	{"blocklist_ips", checkSpamIP},
+	{"duplicate", checkSpamDuplicate},
+	{"velocity", checkSpamVelocity},
+}
+
+// SpamResult 垃圾评论检测结果
+type SpamResult struct {
+	Score   int
+	Reasons []string
+}
+
+// RegisterSpamRule 注册额外的垃圾评论检测规则
+func RegisterSpamRule(name string, rule SpamRule) {
+	spamRules = append(spamRules, spamRule{name: name, check: rule})
+}
+
+// scoreComment 依次执行所有检测规则并累加分数
+func scoreComment(input SpamInput) SpamResult {
+	input.Links = commentLinkPattern.FindAllString(input.Content, -1)
+
+	var result SpamResult
+	for _, rule := range spamRules {
+		score, reason := rule.check(&input)
+		if score == 0 {
+			continue
+		}
+		result.Score += score
+		result.Reasons = append(result.Reasons, rule.name+": "+reason)
+	}
+	return result
+}
+
+// spamVerdict 根据分数和审核方式决定评论状态：超过垃圾阈值直接标记为垃圾评论，
+// 自动审核模式下不超过通过阈值的评论直接通过，其余进入待审核队列
+func spamVerdict(score int, moderation string) string {
+	if score >= config.GetEnvInt("COMMENT_SPAM_THRESHOLD", 10) {
+		return models.CommentStatusSpam
+	}
+	if moderation == models.CommentModerationAuto && score <= config.GetEnvInt("COMMENT_APPROVE_THRESHOLD", 0) {
+		return models.CommentStatusApproved
+	}
+	return models.CommentStatusPending
+}
+
+// assessComment 决定新发表或编辑后的评论状态并记录检测分数：管理员的评论直接通过，其余评论经垃圾评论检测后决定状态，
+// 可信用户的评论只要未被判定为垃圾评论即自动通过，游客和被标记为需审核的用户的评论始终进入审核
+func assessComment(comment *models.Comment, user models.User, settings models.CommentSettings, honeypot string) {
+	if user.Role == "admin" {
+		comment.Status = models.CommentStatusApproved
+		comment.SpamScore = 0
+		comment.SpamReasons = ""
+		return
+	}
+
+	spam := scoreComment(SpamInput{
+		Content:  comment.Content,
+		PostID:   comment.PostID,
+		UserID:   user.ID,
+		IP:       comment.IP,
+		Honeypot: honeypot,
+	})
+	comment.SpamScore = spam.Score
+	comment.SpamReasons = strings.Join(spam.Reasons, "; ")
+	comment.Status = spamVerdict(spam.Score, settings.Moderation)
+	if comment.Status == models.CommentStatusSpam {
+		return
+	}
+
+	switch {
+	case comment.UserID == nil, user.CommentTrust == models.CommentTrustModerated:
+		comment.Status = models.CommentStatusPending
+	case commenterStats(user).Trusted:
+		comment.Status = models.CommentStatusApproved
+	}
+}
+
+// envList 读取逗号分隔的配置列表，忽略空项并转为小写
+func envList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(config.GetEnv(key, ""), ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// checkSpamHoneypot 填写了隐藏字段的几乎一定是机器人
+func checkSpamHoneypot(input *SpamInput) (int, string) {
+	if input.Honeypot != "" {
+		return 100, "填写了隐藏字段"
+	}
+	return 0, ""
+}
+
+// checkSpamLinks 链接数量超过上限时，每多一个链接加分
+func checkSpamLinks(input *SpamInput) (int, string) {
+	limit := config.GetEnvInt("COMMENT_MAX_LINKS", 2)
+	if len(input.Links) <= limit {
+		return 0, ""
+	}
+	return 3 * (len(input.Links) - limit), "包含 " + strconv.Itoa(len(input.Links)) + " 个链接"
+}
+
+// checkSpamWords 包含屏蔽词
+func checkSpamWords(input *SpamInput) (int, string) {
+	content := strings.ToLower(input.Content)
+	var hits []string
+	for _, word := range envList("COMMENT_BLOCKLIST_WORDS") {
+		if strings.Contains(content, word) {
+			hits = append(hits, word)
+		}
+	}
+	if len(hits) == 0 {
+		return 0, ""
+	}
+	return 5 * len(hits), "包含屏蔽词 " + strings.Join(hits, ",")
+}
+
+// checkSpamDomains 链接指向屏蔽的域名 (包括其子域名)
+func checkSpamDomains(input *SpamInput) (int, string) {
+	domains := envList("COMMENT_BLOCKLIST_DOMAINS")
+	if len(domains) == 0 {
+		return 0, ""
+	}
+
+	var hits []string
+	for _, link := range input.Links {
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		u, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		for _, domain := range domains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				hits = append(hits, host)
+			}
+		}
+	}
+	if len(hits) == 0 {
+		return 0, ""
+	}
+	return 10 * len(hits), "链接指向屏蔽域名 " + strings.Join(hits, ",")
+}
+
+// checkSpamIP 来源IP在屏蔽列表中，支持单个IP和CIDR网段
+func checkSpamIP(input *SpamInput) (int, string) {
+	ip := net.ParseIP(input.IP)
+	if ip == nil {
+		return 0, ""
+	}
+	for _, blocked := range envList("COMMENT_BLOCKLIST_IPS") {
+		if _, network, err := net.ParseCIDR(blocked); err == nil {
+			if network.Contains(ip) {
+				return 10, "来源IP " + input.IP + " 属于屏蔽网段"
+			}
+		} else if blockedIP := net.ParseIP(blocked); blockedIP != nil && blockedIP.Equal(ip) {
+			return 10, "来源IP " + input.IP + " 已被屏蔽"
+		}
+	}
+	return 0, ""
+}
+
+// commentContentHash 评论内容的哈希，存入带索引的 content_hash 列，避免在 TEXT 列上比较全文
+func commentContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkSpamDuplicate 近期已有相同内容的评论
+func checkSpamDuplicate(input *SpamInput) (int, string) {
+	since := time.Now().Add(-config.GetEnvDuration("COMMENT_DUPLICATE_WINDOW", 24*time.Hour))
+
+	var count int64
+	config.DB.Unscoped().Model(&models.Comment{}).
+		Where("content_hash = ? AND created_at > ?", commentContentHash(input.Content), since).
+		Count(&count)
+	if count == 0 {
+		return 0, ""
+	}
+	return 5, "近期已出现 " + strconv.FormatInt(count, 10) + " 条相同内容的评论"
+}
+
+// checkSpamVelocity 同一用户或IP短时间内发表评论过多
+func checkSpamVelocity(input *SpamInput) (int, string) {
+	window := config.GetEnvDuration("COMMENT_VELOCITY_WINDOW", time.Minute)
+	limit := config.GetEnvInt("COMMENT_VELOCITY_LIMIT", 3)
+
+	db := config.DB.Unscoped().Model(&models.Comment{}).Where("created_at > ?", time.Now().Add(-window))
+	switch {
+	case input.UserID != 0 && input.IP != "":
+		db = db.Where("user_id = ? OR ip = ?", input.UserID, input.IP)
+	case input.UserID != 0:
+		db = db.Where("user_id = ?", input.UserID)
+	case input.IP != "":
+		db = db.Where("ip = ?", input.IP)
+	default:
+		return 0, ""
+	}
+
+	var count int64
+	db.Count(&count)
+	if int(count) < limit {
+		return 0, ""
+	}
+	return 5, window.String() + " 内已发表 " + strconv.FormatInt(count, 10) + " 条评论"
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/alvinhmg/blog/models"
+)
+
+func TestSpamRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  SpamRule
+		env   map[string]string
+		input SpamInput
+		score int
+	}{
+		{"蜜罐字段为空", checkSpamHoneypot, nil, SpamInput{}, 0},
+		{"填写了蜜罐字段", checkSpamHoneypot, nil, SpamInput{Honeypot: "x"}, 100},
+
+		{"链接数量未超过上限", checkSpamLinks, nil, SpamInput{Links: []string{"http://a.com", "http://b.com"}}, 0},
+		{"链接数量超过上限", checkSpamLinks, nil, SpamInput{Links: []string{"http://a.com", "http://b.com", "http://c.com", "http://d.com"}}, 6},
+		{"自定义链接上限", checkSpamLinks, map[string]string{"COMMENT_MAX_LINKS": "0"}, SpamInput{Links: []string{"http://a.com"}}, 3},
+
+		{"未配置屏蔽词", checkSpamWords, nil, SpamInput{Content: "buy cheap pills"}, 0},
+		{"命中屏蔽词且忽略大小写", checkSpamWords, map[string]string{"COMMENT_BLOCKLIST_WORDS": "cheap, Pills"}, SpamInput{Content: "Buy CHEAP pills"}, 10},
+		{"未命中屏蔽词", checkSpamWords, map[string]string{"COMMENT_BLOCKLIST_WORDS": "casino"}, SpamInput{Content: "写得很好"}, 0},
+
+		{"未配置屏蔽域名", checkSpamDomains, nil, SpamInput{Links: []string{"http://spam.com"}}, 0},
+		{"链接指向屏蔽域名", checkSpamDomains, map[string]string{"COMMENT_BLOCKLIST_DOMAINS": "spam.com"}, SpamInput{Links: []string{"https://spam.com/x"}}, 10},
+		{"链接指向屏蔽域名的子域名", checkSpamDomains, map[string]string{"COMMENT_BLOCKLIST_DOMAINS": "spam.com"}, SpamInput{Links: []string{"www.shop.spam.com"}}, 10},
+		{"相似但不同的域名", checkSpamDomains, map[string]string{"COMMENT_BLOCKLIST_DOMAINS": "spam.com"}, SpamInput{Links: []string{"http://notspam.com"}}, 0},
+
+		{"未配置屏蔽IP", checkSpamIP, nil, SpamInput{IP: "1.2.3.4"}, 0},
+		{"IP在屏蔽列表中", checkSpamIP, map[string]string{"COMMENT_BLOCKLIST_IPS": "1.2.3.4"}, SpamInput{IP: "1.2.3.4"}, 10},
+		{"IP属于屏蔽网段", checkSpamIP, map[string]string{"COMMENT_BLOCKLIST_IPS": "10.0.0.0/8"}, SpamInput{IP: "10.1.2.3"}, 10},
+		{"IP不在屏蔽网段", checkSpamIP, map[string]string{"COMMENT_BLOCKLIST_IPS": "10.0.0.0/8"}, SpamInput{IP: "11.1.2.3"}, 0},
+		{"无效的来源IP", checkSpamIP, map[string]string{"COMMENT_BLOCKLIST_IPS": "1.2.3.4"}, SpamInput{IP: "unknown"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			score, reason := tt.rule(&tt.input)
+			if score != tt.score {
+				t.Errorf("score = %d, want %d (reason: %q)", score, tt.score, reason)
+			}
+			if (score == 0) != (reason == "") {
+				t.Errorf("score = %d but reason = %q", score, reason)
+			}
+		})
+	}
+}
+
+func TestSpamVerdict(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		score      int
+		moderation string
+		want       string
+	}{
+		{"人工审核时零分进入待审核", nil, 0, models.CommentModerationRequired, models.CommentStatusPending},
+		{"自动审核时零分直接通过", nil, 0, models.CommentModerationAuto, models.CommentStatusApproved},
+		{"自动审核时超过通过阈值进入待审核", nil, 1, models.CommentModerationAuto, models.CommentStatusPending},
+		{"达到垃圾阈值", nil, 10, models.CommentModerationAuto, models.CommentStatusSpam},
+		{"低于垃圾阈值", nil, 9, models.CommentModerationRequired, models.CommentStatusPending},
+		{"自定义垃圾阈值", map[string]string{"COMMENT_SPAM_THRESHOLD": "5"}, 5, models.CommentModerationRequired, models.CommentStatusSpam},
+		{"自定义通过阈值", map[string]string{"COMMENT_APPROVE_THRESHOLD": "3"}, 3, models.CommentModerationAuto, models.CommentStatusApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if got := spamVerdict(tt.score, tt.moderation); got != tt.want {
+				t.Errorf("spamVerdict(%d, %q) = %q, want %q", tt.score, tt.moderation, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommentDepth(t *testing.T) {
+	// 评论 1 为顶层评论，2 回复 1，3 回复 2，依此类推；5 的父评论 99 不存在
+	parents := map[uint]*uint{1: nil}
+	for id := uint(2); id <= 4; id++ {
+		parent := id - 1
+		parents[id] = &parent
+	}
+	missing := uint(99)
+	parents[5] = &missing
+
+	parentOf := func(id uint) (*uint, error) {
+		parent, ok := parents[id]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return parent, nil
+	}
+	id := func(v uint) *uint { return &v }
+
+	tests := []struct {
+		name     string
+		parentID *uint
+		limit    int
+		want     int
+	}{
+		{"顶层评论", nil, 5, 1},
+		{"回复顶层评论", id(1), 5, 2},
+		{"第四层", id(3), 5, 4},
+		{"第五层", id(4), 5, 5},
+		{"超过上限后停止查找", id(4), 2, 3},
+		{"祖先评论不存在时停止查找", id(5), 5, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commentDepth(tt.parentID, tt.limit, parentOf); got != tt.want {
+				t.Errorf("commentDepth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
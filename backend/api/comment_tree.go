@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"gorm.io/gorm"
+)
+
+// 评论排序方式
+const (
+	commentSortOldest = "oldest"
+	commentSortNewest = "newest"
+	commentSortTop    = "top"
+)
+
+// commentCursor 评论分页游标，记录上一页最后一条评论的排序值
+type commentCursor struct {
+	Sort      string    `json:"s"`
+	CreatedAt time.Time `json:"t"`
+	Score     int64     `json:"n"`
+	ID        uint      `json:"id"`
+}
+
+// maxCommentDepth 评论允许的最大嵌套层数 (顶层评论为第1层)
+func maxCommentDepth() int {
+	return config.GetEnvInt("COMMENT_MAX_DEPTH", 5)
+}
+
+// publicCommentFields 公开接口中不返回评论的审核信息和游客邮箱
+func publicCommentFields(db *gorm.DB) *gorm.DB {
+	return db.Omit("ip", "spam_score", "spam_reasons", "moderated_by_id", "moderated_at", "guest_email", "auto_hidden")
+}
+
+// publicUserFields 公开接口中只返回评论用户的基本信息
+func publicUserFields(db *gorm.DB) *gorm.DB {
+	return db.Select("id, username, nickname, avatar, role")
+}
+
+// encodeCommentCursor 生成下一页游标
+func encodeCommentCursor(sort string, comment models.Comment) string {
+	data, _ := json.Marshal(commentCursor{
+		Sort:      sort,
+		CreatedAt: comment.CreatedAt,
+		Score:     int64(comment.Score),
+		ID:        comment.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCommentCursor 解析游标，排序方式与游标不一致时视为无效
+func decodeCommentCursor(raw, sort string) (*commentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errors.New("无效的分页游标")
+	}
+	var cursor commentCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort {
+		return nil, errors.New("无效的分页游标")
+	}
+	return &cursor, nil
+}
+
+// commentThreadScope 按排序方式和游标对一层评论排序并定位到下一页
+func commentThreadScope(sort string, cursor *commentCursor) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch sort {
+		case commentSortNewest:
+			if cursor != nil {
+				db = db.Where("comment.created_at < ? OR (comment.created_at = ? AND comment.id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+			}
+			return db.Order("comment.created_at DESC, comment.id DESC")
+		case commentSortTop:
+			if cursor != nil {
+				db = db.Where("comment.score < ? OR (comment.score = ? AND comment.id < ?)", cursor.Score, cursor.Score, cursor.ID)
+			}
+			return db.Order("comment.score DESC, comment.id DESC")
+		default:
+			if cursor != nil {
+				db = db.Where("comment.created_at > ? OR (comment.created_at = ? AND comment.id > ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+			}
+			return db.Order("comment.created_at ASC, comment.id ASC")
+		}
+	}
+}
+
+// fillReplyCounts 批量填充评论已通过审核的直接回复数
+func fillReplyCounts(comments []*models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	var counts []struct {
+		ParentID uint
+		Count    int
+	}
+	err := config.DB.Model(&models.Comment{}).
+		Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ? AND status = ?", ids, models.CommentStatusApproved).
+		Group("parent_id").
+		Scan(&counts).Error
+	if err != nil {
+		return err
+	}
+
+	countMap := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		countMap[count.ParentID] = count.Count
+	}
+	for _, comment := range comments {
+		comment.ReplyCount = countMap[comment.ID]
+	}
+	return nil
+}
+
+// loadCommentReplies 逐层加载已通过审核的回复，共加载 depth 层 (不含 roots 本身)，每层回复按时间先后排列
+func loadCommentReplies(roots []models.Comment, depth int) error {
+	level := make([]*models.Comment, 0, len(roots))
+	for i := range roots {
+		level = append(level, &roots[i])
+	}
+	if err := fillReplyCounts(level); err != nil {
+		return err
+	}
+
+	for ; depth > 0 && len(level) > 0; depth-- {
+		parentIDs := make([]uint, 0, len(level))
+		parents := make(map[uint]*models.Comment, len(level))
+		for _, comment := range level {
+			if comment.ReplyCount == 0 {
+				continue
+			}
+			parentIDs = append(parentIDs, comment.ID)
+			parents[comment.ID] = comment
+		}
+		if len(parentIDs) == 0 {
+			break
+		}
+
+		var replies []models.Comment
+		err := config.DB.Scopes(publicCommentFields).Preload("User", publicUserFields).
+			Where("parent_id IN ? AND status = ?", parentIDs, models.CommentStatusApproved).
+			Order("created_at ASC, id ASC").
+			Find(&replies).Error
+		if err != nil {
+			return err
+		}
+
+		// 先挂到父评论上，再取出指向切片元素的指针作为下一层
+		for _, reply := range replies {
+			parent := parents[*reply.ParentID]
+			parent.Replies = append(parent.Replies, reply)
+		}
+		next := make([]*models.Comment, 0, len(replies))
+		for _, parent := range parents {
+			for i := range parent.Replies {
+				next = append(next, &parent.Replies[i])
+			}
+		}
+		if err := fillReplyCounts(next); err != nil {
+			return err
+		}
+		level = next
+	}
+	return nil
+}
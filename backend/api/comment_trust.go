@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// CommentTrustRequest 设置用户评论信任级别请求
+type CommentTrustRequest struct {
+	Trust string `json:"trust"` // auto, trusted, moderated
+}
+
+// CommenterStats 用户的历史评论审核统计
+type CommenterStats struct {
+	UserID   uint   `json:"user_id"`
+	Trust    string `json:"trust"`    // 管理员设置的信任级别
+	Approved int64  `json:"approved"` // 已通过的评论数
+	Rejected int64  `json:"rejected"` // 被拒绝或标记为垃圾的评论数
+	Trusted  bool   `json:"trusted"`  // 当前评论是否会自动通过
+}
+
+// commenterStats 统计用户的历史评论，并判断其评论是否自动通过：
+// 管理员标记为可信的用户始终通过，标记为需审核的用户始终进入审核，
+// 其余用户在已通过评论数达到 COMMENT_TRUST_THRESHOLD 且没有被拒绝过的评论时自动通过 (阈值为0时关闭)
+func commenterStats(user models.User) CommenterStats {
+	stats := CommenterStats{UserID: user.ID, Trust: user.CommentTrust}
+	if stats.Trust == "" {
+		stats.Trust = models.CommentTrustAuto
+	}
+
+	config.DB.Model(&models.Comment{}).
+		Where("user_id = ? AND status = ?", user.ID, models.CommentStatusApproved).
+		Count(&stats.Approved)
+	config.DB.Model(&models.Comment{}).
+		Where("user_id = ? AND status IN ?", user.ID, []string{models.CommentStatusRejected, models.CommentStatusSpam}).
+		Count(&stats.Rejected)
+
+	switch stats.Trust {
+	case models.CommentTrustTrusted:
+		stats.Trusted = true
+	case models.CommentTrustModerated:
+		stats.Trusted = false
+	default:
+		threshold := config.GetEnvInt("COMMENT_TRUST_THRESHOLD", 3)
+		stats.Trusted = threshold > 0 && stats.Approved >= int64(threshold) && stats.Rejected == 0
+	}
+	return stats
+}
+
+// findCommenter 按路由参数查询用户，失败时直接写入错误响应
+func findCommenter(ctx *app.RequestContext) (models.User, bool) {
+	var user models.User
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的用户ID",
+		})
+		return user, false
+	}
+	if err := config.DB.First(&user, id).Error; err != nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "用户不存在",
+		})
+		return user, false
+	}
+	return user, true
+}
+
+// GetCommenterTrust 获取用户的评论信任级别及历史评论统计
+func GetCommenterTrust(c context.Context, ctx *app.RequestContext) {
+	user, ok := findCommenter(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取用户评论信任级别成功",
+		"data":    commenterStats(user),
+	})
+}
+
+// SetCommenterTrust 将用户标记为可信、始终需要审核或恢复自动判断
+func SetCommenterTrust(c context.Context, ctx *app.RequestContext) {
+	user, ok := findCommenter(ctx)
+	if !ok {
+		return
+	}
+
+	var req CommentTrustRequest
+	if err := ctx.BindAndValidate(&req); err != nil || !models.IsValidCommentTrust(req.Trust) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误，trust 可选 auto、trusted、moderated",
+		})
+		return
+	}
+
+	if err := config.DB.Model(&user).Update("comment_trust", req.Trust).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "设置用户评论信任级别失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	user.CommentTrust = req.Trust
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "设置用户评论信任级别成功",
+		"data":    commenterStats(user),
+	})
+}
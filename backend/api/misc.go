@@ -11,6 +11,8 @@ import (
 
 // GetHomePageData 获取首页所需数据 (示例：最新文章、热门文章、热门标签/分类)
 func GetHomePageData(c context.Context, ctx *app.RequestContext) {
+	var pinnedPosts []models.Post
+	var featuredPosts []models.Post
 	var latestPosts []models.Post
 	var hotPosts []models.Post
 	var hotCategories []struct {
@@ -22,6 +24,18 @@ func GetHomePageData(c context.Context, ctx *app.RequestContext) {
 		PostCount int `json:"post_count"`
 	}
 
+	// 获取已发布的置顶文章 (按置顶顺序)
+	if err := config.DB.Preload("Author").Preload("Categories").Preload("Tags").Where("is_pinned = ? AND status = ?", true, models.PostStatusPublished).Order(pinnedFirstOrder).Find(&pinnedPosts).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "获取置顶文章失败", "error": err.Error()})
+		return
+	}
+
+	// 获取精选文章 (排除已过期的精选，取5篇)
+	if err := config.DB.Preload("Author").Preload("Categories").Preload("Tags").Scopes(activeFeaturedScope).Order("created_at DESC").Limit(5).Find(&featuredPosts).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "获取精选文章失败", "error": err.Error()})
+		return
+	}
+
 	// 获取最新文章 (示例：取5篇)
 	if err := config.DB.Preload("Author").Preload("Categories").Preload("Tags").Order("created_at DESC").Limit(5).Find(&latestPosts).Error; err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "获取最新文章失败", "error": err.Error()})
@@ -64,6 +78,8 @@ func GetHomePageData(c context.Context, ctx *app.RequestContext) {
 		"code":    200,
 		"message": "获取首页数据成功",
 		"data": map[string]interface{}{
+			"pinned_posts":   pinnedPosts,
+			"featured_posts": featuredPosts,
 			"latest_posts":   latestPosts,
 			"hot_posts":      hotPosts,
 			"hot_categories": hotCategories,
@@ -93,6 +109,7 @@ func GetArchiveData(c context.Context, ctx *app.RequestContext) {
 	dbResult := config.DB.Model(&models.Post{}).
 		Select("DATE_FORMAT(created_at, '%Y-%m') as year_month, id, title, slug, created_at"). // 选择需要的字段
 		Where("status = ?", "published").
+		Scopes(langScope(ctx)).
 		Order("created_at DESC").
 		Scan(&results)
 
@@ -9,6 +9,7 @@ import (
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GetPosts 获取文章列表 (支持分页、分类、标签过滤)
@@ -29,7 +30,7 @@ func GetPosts(c context.Context, ctx *app.RequestContext) {
 	}
 
 	// 构建查询
-	db := config.DB.Model(&models.Post{}).Preload("Author").Preload("Categories").Preload("Tags")
+	db := config.DB.Model(&models.Post{}).Preload("Author").Scopes(preloadCoauthors).Preload("Categories").Preload("Tags").Scopes(langScope(ctx), metaFilterScope(ctx))
 
 	// 分类过滤
 	if categoryIDStr != "" {
@@ -54,12 +55,12 @@ func GetPosts(c context.Context, ctx *app.RequestContext) {
 	// 计算总数
 	db.Count(&total)
 
-	// 查询文章列表，包含作者信息
+	// 查询文章列表，包含作者信息，置顶文章排在最前
 	offset := (page - 1) * pageSize
 	if offset < 0 {
 		offset = 0
 	}
-	result := db.Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&posts)
+	result := db.Order(pinnedFirstOrder).Limit(pageSize).Offset(offset).Find(&posts)
 	if result.Error != nil {
 		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
 			"code":    500,
@@ -69,6 +70,10 @@ func GetPosts(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
+	// 填充自定义字段并标记当前用户的点赞状态
+	fillPostMeta(posts)
+	fillLikedByMe(ctx, posts)
+
 	// 计算总页数
 	totalPage := int64(0)
 	if pageSize > 0 {
@@ -96,7 +101,11 @@ func GetPost(c context.Context, ctx *app.RequestContext) {
 
 	// 查询文章详情
 	var post models.Post
-	result := config.DB.Preload("Author").Preload("Categories").Preload("Tags").Preload("Comments").Preload("Comments.User").First(&post, id)
+	result := config.DB.Preload("Author").Scopes(preloadCoauthors).Preload("Categories").Preload("Tags").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return publicCommentFields(db).Where("status = ?", models.CommentStatusApproved)
+		}).Preload("Comments.User", publicUserFields).
+		First(&post, id)
 	if result.Error != nil {
 		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
 			"code":    404,
@@ -106,20 +115,41 @@ func GetPost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
-	// 增加浏览量 (使用事务保证原子性)
-	err := config.DB.Transaction(func(tx *gorm.DB) error {
-		return tx.Model(&post).Update("view_count", gorm.Expr("view_count + ?", 1)).Error
-	})
+	// 记录浏览量 (按访客去重、忽略爬虫，增量在内存中缓冲后批量写入)
+	post.ViewCount += recordView(ctx, post.ID)
 
+	// 加载系列导航信息
+	seriesNav, err := loadSeriesNav(&post)
 	if err != nil {
-		// 记录日志，但不阻塞响应
-		println("增加浏览量失败:", err.Error())
+		println("加载系列导航失败:", err.Error())
 	}
+	post.Series = seriesNav
 
-	// 重新加载文章数据以获取最新的浏览量
-	config.DB.Preload("Author").Preload("Categories").Preload("Tags").Preload("Comments").Preload("Comments.User").First(&post, id)
+	// 加载其他语言版本链接
+	translations, err := loadTranslations(post)
+	if err != nil {
+		println("加载翻译版本失败:", err.Error())
+	}
+	post.Translations = translations
+
+	// 填充自定义字段和评论反应，并标记当前用户的点赞状态
+	single := []models.Post{post}
+	fillPostMeta(single)
+	fillLikedByMe(ctx, single)
+	post = single[0]
+	fillCommentReactions(ctx, post.Comments)
+
+	// 实际生效的评论设置
+	commentSettings := resolveCommentSettings(post)
+	post.CommentSettings = &commentSettings
+
+	// 管理员查看时附带编辑锁信息
+	if user, exists := ctx.Get("user"); exists && user.(models.User).Role == "admin" {
+		post.EditLock, _ = activeEditLock(post.ID)
+	}
 
 	// 返回文章详情
+	setPostETag(ctx, post)
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
 		"code":    200,
 		"message": "获取文章详情成功",
@@ -153,7 +183,7 @@ func SearchPosts(c context.Context, ctx *app.RequestContext) {
 	var posts []models.Post
 	var total int64
 
-	db := config.DB.Model(&models.Post{}).Preload("Author").Preload("Categories").Preload("Tags")
+	db := config.DB.Model(&models.Post{}).Preload("Author").Scopes(preloadCoauthors).Preload("Categories").Preload("Tags").Scopes(langScope(ctx))
 
 	// 在标题和内容中进行不区分大小写的模糊搜索
 	searchQuery := "%" + query + "%"
@@ -177,6 +207,10 @@ func SearchPosts(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
+	// 标记当前用户的点赞状态，填充自定义字段
+	fillLikedByMe(ctx, posts)
+	fillPostMeta(posts)
+
 	// 计算总页数
 	totalPage := int64(0)
 	if pageSize > 0 {
@@ -196,8 +230,18 @@ func SearchPosts(c context.Context, ctx *app.RequestContext) {
 	})
 }
 
-// LikePost 点赞文章
+// LikePost 点赞文章 (每个用户对同一篇文章只能点赞一次)
 func LikePost(c context.Context, ctx *app.RequestContext) {
+	setPostLike(ctx, true)
+}
+
+// UnlikePost 取消点赞文章
+func UnlikePost(c context.Context, ctx *app.RequestContext) {
+	setPostLike(ctx, false)
+}
+
+// setPostLike 记录或删除当前用户的点赞，并根据点赞记录重新计算文章点赞数
+func setPostLike(ctx *app.RequestContext, liked bool) {
 	idStr := ctx.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -208,6 +252,15 @@ func LikePost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
+			"code":    401,
+			"message": "请先登录",
+		})
+		return
+	}
+
 	var post models.Post
 	// 检查文章是否存在
 	if err := config.DB.First(&post, id).Error; err != nil {
@@ -226,26 +279,77 @@ func LikePost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
-	// 增加点赞数 (使用事务保证原子性)
+	like := models.PostLike{UserID: userID.(uint), PostID: post.ID}
 	err = config.DB.Transaction(func(tx *gorm.DB) error {
-		return tx.Model(&post).Update("like_count", gorm.Expr("like_count + ?", 1)).Error
+		if liked {
+			// 唯一索引冲突时忽略，保证重复点赞不会重复计数
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Where("user_id = ? AND post_id = ?", like.UserID, like.PostID).Delete(&models.PostLike{}).Error; err != nil {
+				return err
+			}
+		}
+		// 以点赞记录为准同步点赞数
+		return tx.Model(&post).UpdateColumn("like_count",
+			tx.Model(&models.PostLike{}).Select("COUNT(*)").Where("post_id = ?", post.ID)).Error
 	})
 
+	message := "点赞成功"
+	failMessage := "点赞失败"
+	if !liked {
+		message = "取消点赞成功"
+		failMessage = "取消点赞失败"
+	}
 	if err != nil {
 		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
 			"code":    500,
-			"message": "点赞失败",
+			"message": failMessage,
 			"error":   err.Error(),
 		})
 		return
 	}
 
+	config.DB.Select("like_count").First(&post, post.ID)
+
 	// 返回成功信息和更新后的点赞数
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
 		"code":    200,
-		"message": "点赞成功",
+		"message": message,
 		"data": map[string]interface{}{
-			"like_count": post.LikeCount + 1, // 返回预期的点赞数
+			"like_count":  post.LikeCount,
+			"liked_by_me": liked,
 		},
 	})
 }
+
+// fillLikedByMe 为已登录用户标记文章是否已点赞，匿名访问时不做处理
+func fillLikedByMe(ctx *app.RequestContext, posts []models.Post) {
+	userID, exists := ctx.Get("userID")
+	if !exists || len(posts) == 0 {
+		return
+	}
+
+	postIDs := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	var likedIDs []uint
+	if err := config.DB.Model(&models.PostLike{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &likedIDs).Error; err != nil {
+		println("查询点赞状态失败:", err.Error())
+		return
+	}
+
+	likedSet := make(map[uint]bool, len(likedIDs))
+	for _, postID := range likedIDs {
+		likedSet[postID] = true
+	}
+	for i := range posts {
+		liked := likedSet[posts[i].ID]
+		posts[i].LikedByMe = &liked
+	}
+}
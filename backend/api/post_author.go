@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// PostAuthorInput 共同作者请求项
+type PostAuthorInput struct {
+	UserID uint   `json:"user_id"`
+	Role   string `json:"role"` // author, editor, translator，为空时默认为 author
+}
+
+// UpdatePostAuthorsRequest 设置共同作者请求
+type UpdatePostAuthorsRequest struct {
+	Coauthors []PostAuthorInput `json:"coauthors"` // 按显示顺序排列，不含主作者
+}
+
+// preloadCoauthors 按顺序预加载共同作者及其用户信息
+func preloadCoauthors(db *gorm.DB) *gorm.DB {
+	return db.Preload("Coauthors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Preload("Coauthors.User")
+}
+
+// checkPostAuthors 校验共同作者：用户存在、角色有效、不重复且不包含主作者
+func checkPostAuthors(primaryID uint, coauthors []PostAuthorInput) error {
+	if len(coauthors) == 0 {
+		return nil
+	}
+
+	seen := make(map[uint]bool, len(coauthors))
+	userIDs := make([]uint, 0, len(coauthors))
+	for _, coauthor := range coauthors {
+		switch coauthor.Role {
+		case "", models.PostAuthorRoleAuthor, models.PostAuthorRoleEditor, models.PostAuthorRoleTranslator:
+		default:
+			return fmt.Errorf("无效的作者角色: %s", coauthor.Role)
+		}
+		if coauthor.UserID == primaryID {
+			return errors.New("共同作者中不能包含主作者")
+		}
+		if seen[coauthor.UserID] {
+			return errors.New("共同作者中包含重复的用户")
+		}
+		seen[coauthor.UserID] = true
+		userIDs = append(userIDs, coauthor.UserID)
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.User{}).Where("id IN ?", userIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(userIDs) {
+		return errors.New("部分共同作者不存在")
+	}
+	return nil
+}
+
+// replacePostAuthors 按给定顺序替换文章的共同作者
+func replacePostAuthors(tx *gorm.DB, postID uint, coauthors []PostAuthorInput) error {
+	if err := tx.Where("post_id = ?", postID).Delete(&models.PostAuthor{}).Error; err != nil {
+		return err
+	}
+	if len(coauthors) == 0 {
+		return nil
+	}
+
+	items := make([]models.PostAuthor, 0, len(coauthors))
+	for i, coauthor := range coauthors {
+		role := coauthor.Role
+		if role == "" {
+			role = models.PostAuthorRoleAuthor
+		}
+		items = append(items, models.PostAuthor{
+			PostID:   postID,
+			UserID:   coauthor.UserID,
+			Role:     role,
+			Position: i + 1,
+		})
+	}
+	return tx.Omit("User").Create(&items).Error
+}
+
+// canEditPost 判断用户能否编辑文章：主作者、共同作者或管理员
+func canEditPost(post models.Post, userID uint) bool {
+	if post.AuthorID == userID {
+		return true
+	}
+
+	var count int64
+	config.DB.Model(&models.PostAuthor{}).Where("post_id = ? AND user_id = ?", post.ID, userID).Count(&count)
+	if count > 0 {
+		return true
+	}
+
+	var user models.User
+	config.DB.First(&user, userID)
+	return user.Role == "admin"
+}
+
+// findEditablePost 按路由参数查询文章并检查当前用户能否编辑，失败时直接写入错误响应
+func findEditablePost(ctx *app.RequestContext) (models.Post, bool) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return post, false
+	}
+	userID, _ := ctx.Get("userID")
+	if !canEditPost(post, userID.(uint)) {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "无权编辑该文章",
+		})
+		return post, false
+	}
+	return post, true
+}
+
+// UpdatePostAuthors 设置文章的共同作者
+func UpdatePostAuthors(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdatePostAuthorsRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err := checkPostAuthors(post.AuthorID, req.Coauthors); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return replacePostAuthors(tx, post.ID, req.Coauthors)
+	}); err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "设置共同作者失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.Preload("Author").Scopes(preloadCoauthors).First(&post, post.ID)
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "设置共同作者成功",
+		"data":    post,
+	})
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// AutosaveRequest 自动保存请求
+type AutosaveRequest struct {
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	Excerpt     string `json:"excerpt"`
+	CoverImage  string `json:"cover_image"`
+	BaseVersion *int   `json:"base_version"` // 开始编辑时的文章版本，为空时沿用已有草稿或当前版本
+}
+
+// findAutosave 查询当前用户对文章的自动保存草稿，不存在时返回nil
+func findAutosave(postID, userID uint) (*models.PostAutosave, error) {
+	var autosave models.PostAutosave
+	result := config.DB.Where("post_id = ? AND user_id = ?", postID, userID).Limit(1).Find(&autosave)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return &autosave, nil
+}
+
+// GetAutosave 获取当前用户对文章的自动保存草稿
+func GetAutosave(c context.Context, ctx *app.RequestContext) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	autosave, err := findAutosave(post.ID, userID.(uint))
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "查询自动保存草稿失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if autosave == nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "没有自动保存的草稿",
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取自动保存草稿成功",
+		"data": map[string]interface{}{
+			"autosave":        autosave,
+			"current_version": post.Version,
+			"outdated":        autosave.BaseVersion != post.Version, // 草稿之后文章是否已被修改
+		},
+	})
+}
+
+// SaveAutosave 自动保存文章草稿，不修改文章的线上内容
+func SaveAutosave(c context.Context, ctx *app.RequestContext) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	var req AutosaveRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	autosave, err := findAutosave(post.ID, userID.(uint))
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "查询自动保存草稿失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if autosave == nil {
+		autosave = &models.PostAutosave{
+			PostID:      post.ID,
+			UserID:      userID.(uint),
+			BaseVersion: post.Version,
+		}
+	}
+	if req.BaseVersion != nil {
+		autosave.BaseVersion = *req.BaseVersion
+	}
+	autosave.Title = req.Title
+	autosave.Content = req.Content
+	autosave.Excerpt = req.Excerpt
+	autosave.CoverImage = req.CoverImage
+
+	if err := config.DB.Save(autosave).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "自动保存失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "自动保存成功",
+		"data":    autosave,
+	})
+}
+
+// PublishAutosave 将自动保存的草稿应用到文章，文章在草稿之后被修改过时返回409 (可通过 force=true 强制覆盖)
+func PublishAutosave(c context.Context, ctx *app.RequestContext) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	autosave, err := findAutosave(post.ID, userID.(uint))
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "查询自动保存草稿失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if autosave == nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "没有自动保存的草稿",
+		})
+		return
+	}
+
+	if autosave.BaseVersion != post.Version && ctx.Query("force") != "true" {
+		respondVersionConflict(ctx, post)
+		return
+	}
+
+	updates := map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	}
+	if autosave.Title != "" {
+		updates["title"] = autosave.Title
+	}
+	if autosave.Content != "" {
+		updates["content"] = autosave.Content
+	}
+	if autosave.Excerpt != "" {
+		updates["excerpt"] = autosave.Excerpt
+	}
+	if autosave.CoverImage != "" {
+		updates["cover_image"] = autosave.CoverImage
+	}
+
+	conflict := false
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&post).Where("version = ?", post.Version).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			conflict = true
+			return nil
+		}
+		return tx.Delete(autosave).Error
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "发布自动保存草稿失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if conflict {
+		var current models.Post
+		config.DB.First(&current, post.ID)
+		respondVersionConflict(ctx, current)
+		return
+	}
+
+	InvalidateRelatedCache()
+
+	config.DB.Preload("Author").Preload("Categories").Preload("Tags").First(&post, post.ID)
+
+	setPostETag(ctx, post)
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "发布自动保存草稿成功",
+		"data":    post,
+	})
+}
+
+// DiscardAutosave 丢弃当前用户的自动保存草稿
+func DiscardAutosave(c context.Context, ctx *app.RequestContext) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	if err := config.DB.Where("post_id = ? AND user_id = ?", post.ID, userID).Delete(&models.PostAutosave{}).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "丢弃自动保存草稿失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "已丢弃自动保存草稿",
+	})
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// 批量操作类型
+const (
+	bulkActionSetStatus        = "set_status"
+	bulkActionAddCategories    = "add_categories"
+	bulkActionRemoveCategories = "remove_categories"
+	bulkActionAddTags          = "add_tags"
+	bulkActionRemoveTags       = "remove_tags"
+	bulkActionChangeAuthor     = "change_author"
+	bulkActionDelete           = "delete"
+)
+
+// maxBulkPosts 单次批量操作的文章数上限
+const maxBulkPosts = 500
+
+// BulkPostRequest 批量操作文章请求
+type BulkPostRequest struct {
+	PostIDs     []uint `json:"post_ids"`
+	Action      string `json:"action"`       // set_status, add_categories, remove_categories, add_tags, remove_tags, change_author, delete
+	Status      string `json:"status"`       // set_status 时使用
+	CategoryIDs []uint `json:"category_ids"` // add_categories/remove_categories 时使用
+	TagIDs      []uint `json:"tag_ids"`      // add_tags/remove_tags 时使用
+	AuthorID    uint   `json:"author_id"`    // change_author 时使用
+	Atomic      bool   `json:"atomic"`       // 为 true 时任意一篇失败则全部回滚
+}
+
+// BulkItemResult 单篇文章的批量操作结果
+type BulkItemResult struct {
+	PostID  uint   `json:"post_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// errBulkAborted 原子模式下有文章失败时用于回滚整个事务
+var errBulkAborted = errors.New("批量操作中存在失败项，已全部回滚")
+
+// BulkUpdatePosts 批量操作文章 (修改状态、增删分类/标签、更换作者、删除)，在同一事务中执行并返回逐项结果
+func BulkUpdatePosts(c context.Context, ctx *app.RequestContext) {
+	var req BulkPostRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	req.PostIDs = uniqueIDs(req.PostIDs)
+	req.CategoryIDs = uniqueIDs(req.CategoryIDs)
+	req.TagIDs = uniqueIDs(req.TagIDs)
+	if len(req.PostIDs) == 0 || len(req.PostIDs) > maxBulkPosts {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "post_ids 不能为空且单次最多操作 500 篇文章",
+		})
+		return
+	}
+
+	// 预先校验操作参数，并加载需要关联的分类、标签
+	var categories []models.Category
+	var tags []models.Tag
+	switch req.Action {
+	case bulkActionSetStatus:
+		if !models.IsValidPostStatus(req.Status) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{"code": 400, "message": "无效的文章状态: " + req.Status})
+			return
+		}
+	case bulkActionAddCategories, bulkActionRemoveCategories:
+		if len(req.CategoryIDs) == 0 {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{"code": 400, "message": "category_ids 不能为空"})
+			return
+		}
+		if err := config.DB.Where("id IN ?", req.CategoryIDs).Find(&categories).Error; err != nil {
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "查询分类失败", "error": err.Error()})
+			return
+		}
+		if len(categories) != len(req.CategoryIDs) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{"code": 400, "message": "部分分类不存在"})
+			return
+		}
+	case bulkActionAddTags, bulkActionRemoveTags:
+		if len(req.TagIDs) == 0 {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{"code": 400, "message": "tag_ids 不能为空"})
+			return
+		}
+		if err := config.DB.Where("id IN ?", req.TagIDs).Find(&tags).Error; err != nil {
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "查询标签失败", "error": err.Error()})
+			return
+		}
+		if len(tags) != len(req.TagIDs) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{"code": 400, "message": "部分标签不存在"})
+			return
+		}
+	case bulkActionChangeAuthor:
+		var author models.User
+		if err := config.DB.First(&author, req.AuthorID).Error; err != nil {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{"code": 400, "message": "新作者不存在"})
+			return
+		}
+	case bulkActionDelete:
+	default:
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{"code": 400, "message": "不支持的批量操作: " + req.Action})
+		return
+	}
+
+	var posts []models.Post
+	if err := config.DB.Where("id IN ?", req.PostIDs).Find(&posts).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "查询文章失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	postMap := make(map[uint]models.Post, len(posts))
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+
+	results := make([]BulkItemResult, 0, len(req.PostIDs))
+	succeeded := 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, postID := range req.PostIDs {
+			result := BulkItemResult{PostID: postID}
+			post, ok := postMap[postID]
+			if !ok {
+				result.Error = "文章不存在"
+				results = append(results, result)
+				continue
+			}
+
+			// 每篇文章使用独立的保存点，单篇失败只回滚该篇
+			itemErr := tx.Transaction(func(itemTx *gorm.DB) error {
+				return applyBulkAction(itemTx, &post, req, categories, tags)
+			})
+			if itemErr != nil {
+				result.Error = itemErr.Error()
+			} else {
+				result.Success = true
+				succeeded++
+			}
+			results = append(results, result)
+		}
+		if req.Atomic && succeeded != len(req.PostIDs) {
+			return errBulkAborted
+		}
+		return nil
+	})
+
+	if err != nil {
+		// 整个事务已回滚，成功项也不再生效
+		for i := range results {
+			if results[i].Success {
+				results[i].Success = false
+				results[i].Error = "已回滚"
+			}
+		}
+		status := consts.StatusInternalServerError
+		if err == errBulkAborted {
+			status = consts.StatusConflict
+		}
+		ctx.JSON(status, map[string]interface{}{
+			"code":    status,
+			"message": "批量操作失败",
+			"error":   err.Error(),
+			"data": map[string]interface{}{
+				"results":   results,
+				"succeeded": 0,
+				"failed":    len(results),
+			},
+		})
+		return
+	}
+
+	if succeeded > 0 {
+		InvalidateRelatedCache()
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "批量操作完成",
+		"data": map[string]interface{}{
+			"results":   results,
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+		},
+	})
+}
+
+// applyBulkAction 对单篇文章执行批量操作
+func applyBulkAction(tx *gorm.DB, post *models.Post, req BulkPostRequest, categories []models.Category, tags []models.Tag) error {
+	switch req.Action {
+	case bulkActionSetStatus:
+		// 批量操作仅限管理员，可以直接发布
+		if !models.CanTransitionPostStatus(post.Status, req.Status, true) {
+			return errors.New("不允许将文章状态从 " + post.Status + " 变更为 " + req.Status)
+		}
+		updates := map[string]interface{}{
+			"status":  req.Status,
+			"version": gorm.Expr("version + 1"),
+		}
+		if req.Status == models.PostStatusPendingReview && post.Status != models.PostStatusPendingReview {
+			updates["submitted_at"] = time.Now()
+		}
+		setPublishedAt(*post, req.Status, updates)
+		return tx.Model(post).Updates(updates).Error
+	case bulkActionAddCategories:
+		return tx.Model(post).Association("Categories").Append(categories)
+	case bulkActionRemoveCategories:
+		return tx.Model(post).Association("Categories").Delete(categories)
+	case bulkActionAddTags:
+		return tx.Model(post).Association("Tags").Append(tags)
+	case bulkActionRemoveTags:
+		return tx.Model(post).Association("Tags").Delete(tags)
+	case bulkActionChangeAuthor:
+		// 新的主作者不再同时作为共同作者
+		if err := tx.Where("post_id = ? AND user_id = ?", post.ID, req.AuthorID).Delete(&models.PostAuthor{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(post).Updates(map[string]interface{}{
+			"author_id": req.AuthorID,
+			"version":   gorm.Expr("version + 1"),
+		}).Error
+	case bulkActionDelete:
+		return trashPost(tx, post)
+	}
+	return errors.New("不支持的批量操作")
+}
+
+// uniqueIDs 按原顺序去除重复的ID
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
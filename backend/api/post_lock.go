@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// editLockTTL 编辑锁有效期，持有者需在此时间内发送心跳续期
+func editLockTTL() time.Duration {
+	return config.GetEnvDuration("POST_LOCK_TTL", 2*time.Minute)
+}
+
+// activeEditLock 查询文章当前有效的编辑锁，没有或已过期时返回nil
+func activeEditLock(postID uint) (*models.PostEditLock, error) {
+	var lock models.PostEditLock
+	result := config.DB.Preload("User").Where("post_id = ? AND expires_at > ?", postID, time.Now()).Limit(1).Find(&lock)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return &lock, nil
+}
+
+// GetPostLock 获取文章当前的编辑锁状态
+func GetPostLock(c context.Context, ctx *app.RequestContext) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+
+	lock, err := activeEditLock(post.ID)
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "查询编辑锁失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取编辑锁状态成功",
+		"data": map[string]interface{}{
+			"locked": lock != nil,
+			"lock":   lock,
+		},
+	})
+}
+
+// AcquirePostLock 获取文章编辑锁，锁被他人持有时返回423及持有者信息
+func AcquirePostLock(c context.Context, ctx *app.RequestContext) {
+	takePostLock(ctx, false)
+}
+
+// ForceTakePostLock 强制接管他人持有的编辑锁，只有文章主作者或管理员可以强制接管，共同作者只能等待锁释放或过期
+func ForceTakePostLock(c context.Context, ctx *app.RequestContext) {
+	takePostLock(ctx, true)
+}
+
+// HeartbeatPostLock 编辑锁心跳续期，只有持有者可以续期
+func HeartbeatPostLock(c context.Context, ctx *app.RequestContext) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	now := time.Now()
+	result := config.DB.Model(&models.PostEditLock{}).
+		Where("post_id = ? AND user_id = ? AND expires_at > ?", post.ID, userID, now).
+		Updates(map[string]interface{}{
+			"heartbeat_at": now,
+			"expires_at":   now.Add(editLockTTL()),
+		})
+	if result.Error != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "编辑锁续期失败",
+			"error":   result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		// 锁已过期或已被他人接管
+		lock, _ := activeEditLock(post.ID)
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": "您已不再持有该文章的编辑锁",
+			"data": map[string]interface{}{
+				"locked": lock != nil,
+				"lock":   lock,
+			},
+		})
+		return
+	}
+
+	lock, _ := activeEditLock(post.ID)
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "编辑锁续期成功",
+		"data":    lock,
+	})
+}
+
+// ReleasePostLock 释放编辑锁，只有持有者可以释放
+func ReleasePostLock(c context.Context, ctx *app.RequestContext) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	if err := config.DB.Where("post_id = ? AND user_id = ?", post.ID, userID).Delete(&models.PostEditLock{}).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "释放编辑锁失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "释放编辑锁成功",
+	})
+}
+
+// takePostLock 获取或强制接管编辑锁
+func takePostLock(ctx *app.RequestContext, force bool) {
+	post, ok := findEditablePost(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+	if force && !canForceTakePostLock(post, userID.(uint)) {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "只有文章主作者、编辑或管理员可以强制接管编辑锁",
+		})
+		return
+	}
+
+	var held *models.PostEditLock
+	now := time.Now()
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var lock models.PostEditLock
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("post_id = ?", post.ID).Limit(1).Find(&lock)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// 锁仍有效且由他人持有，非强制接管时不修改
+		if result.RowsAffected > 0 && lock.ExpiresAt.After(now) && lock.UserID != userID.(uint) && !force {
+			held = &lock
+			return nil
+		}
+
+		lock.PostID = post.ID
+		lock.UserID = userID.(uint)
+		lock.HeartbeatAt = now
+		lock.ExpiresAt = now.Add(editLockTTL())
+		if result.RowsAffected == 0 {
+			lock.CreatedAt = now
+		}
+		return tx.Omit("User").Save(&lock).Error
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取编辑锁失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if held != nil {
+		config.DB.First(&held.User, held.UserID)
+		ctx.JSON(consts.StatusLocked, map[string]interface{}{
+			"code":    423,
+			"message": "该文章正在被其他人编辑",
+			"data":    held,
+		})
+		return
+	}
+
+	lock, _ := activeEditLock(post.ID)
+	message := "获取编辑锁成功"
+	if force {
+		message = "已强制接管编辑锁"
+	}
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": message,
+		"data":    lock,
+	})
+}
+
+// canForceTakePostLock 判断用户能否强制接管编辑锁：文章主作者、角色为编辑的共同作者或管理员
+func canForceTakePostLock(post models.Post, userID uint) bool {
+	if post.AuthorID == userID {
+		return true
+	}
+
+	var editors int64
+	config.DB.Model(&models.PostAuthor{}).
+		Where("post_id = ? AND user_id = ? AND role = ?", post.ID, userID, models.PostAuthorRoleEditor).
+		Count(&editors)
+	if editors > 0 {
+		return true
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		return false
+	}
+	return user.Role == "admin"
+}
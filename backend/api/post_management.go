@@ -9,30 +9,42 @@ import (
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
 	"github.com/gosimple/slug"
+	"gorm.io/gorm"
 )
 
 // CreatePostRequest 创建文章请求
 type CreatePostRequest struct {
-	Title      string `json:"title" binding:"required"`
-	Content    string `json:"content" binding:"required"`
-	Excerpt    string `json:"excerpt"`
-	CoverImage string `json:"cover_image"`
-	Status     string `json:"status" binding:"required,oneof=draft published"`
-	Categories []uint `json:"categories"`
-	Tags       []uint `json:"tags"`
-	Slug       string `json:"slug"`
+	Title         string                 `json:"title" vd:"len($)>0"`
+	Content       string                 `json:"content" vd:"len($)>0"`
+	Excerpt       string                 `json:"excerpt"`
+	CoverImage    string                 `json:"cover_image"`
+	Status        string                 `json:"status"` // draft, pending_review, published，为空时默认为草稿，只有管理员可以直接发布
+	Categories    []uint                 `json:"categories"`
+	Tags          []uint                 `json:"tags"`
+	Slug          string                 `json:"slug"`
+	Coauthors     []PostAuthorInput      `json:"coauthors"`      // 共同作者 (不含主作者)
+	Lang          string                 `json:"lang"`           // 语言代码，为空时使用默认语言
+	TranslationOf *uint                  `json:"translation_of"` // 作为该文章的翻译创建，加入其翻译分组
+	SEO           models.SEOMeta         `json:"seo"`
+	CommentPolicy string                 `json:"comment_policy"` // inherit, open, closed, registered，为空时跟随站点设置
+	Meta          map[string]interface{} `json:"meta"`           // 自定义字段值，必填字段必须提供
 }
 
 // UpdatePostRequest 更新文章请求
 type UpdatePostRequest struct {
-	Title      string `json:"title"`
-	Content    string `json:"content"`
-	Excerpt    string `json:"excerpt"`
-	CoverImage string `json:"cover_image"`
-	Status     string `json:"status" binding:"omitempty,oneof=draft published"`
-	Categories []uint `json:"categories"`
-	Tags       []uint `json:"tags"`
-	Slug       string `json:"slug"`
+	Title         string            `json:"title"`
+	Content       string            `json:"content"`
+	Excerpt       string            `json:"excerpt"`
+	CoverImage    string            `json:"cover_image"`
+	Status        string            `json:"status"` // 按文章状态机校验
+	Categories    []uint            `json:"categories"`
+	Tags          []uint            `json:"tags"`
+	Slug          string            `json:"slug"`
+	Coauthors     []PostAuthorInput `json:"coauthors"` // 不为nil时替换共同作者
+	Lang          string            `json:"lang"`
+	Version       *int              `json:"version"` // 编辑所基于的版本号，也可通过 If-Match 请求头传入
+	SEO           *models.SEOMeta   `json:"seo"`     // 不为nil时整体替换 SEO 元数据
+	CommentPolicy string            `json:"comment_policy"`
 }
 
 // CreatePost 创建文章
@@ -58,29 +70,128 @@ func CreatePost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
+	// 校验初始状态 (视为从草稿流转)
+	if req.Status == "" {
+		req.Status = models.PostStatusDraft
+	}
+	if !models.CanTransitionPostStatus(models.PostStatusDraft, req.Status, isAdminRequest(ctx)) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "不允许以该状态创建文章: " + req.Status,
+		})
+		return
+	}
+
+	// 校验自定义字段
+	metaValues, err := newPostMetaValues(req.Meta)
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// 校验共同作者
+	if err := checkPostAuthors(userID.(uint), req.Coauthors); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// 校验 SEO 元数据
+	if err := validateSEOMeta(req.SEO); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// 校验评论策略
+	if req.CommentPolicy == "" {
+		req.CommentPolicy = models.CommentPolicyInherit
+	}
+	if !models.IsValidCommentPolicy(req.CommentPolicy) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的评论策略: " + req.CommentPolicy,
+		})
+		return
+	}
+
+	// 校验语言
+	if req.Lang == "" {
+		req.Lang = defaultLang()
+	}
+	if !isSupportedLang(req.Lang) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "不支持的语言: " + req.Lang,
+		})
+		return
+	}
+
+	// 作为翻译创建时，检查原文并确定翻译分组
+	var translationGroupID *uint
+	if req.TranslationOf != nil {
+		var source models.Post
+		if err := config.DB.First(&source, *req.TranslationOf).Error; err != nil {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "原文不存在",
+			})
+			return
+		}
+		groupID := source.ID
+		if source.TranslationGroupID != nil {
+			groupID = *source.TranslationGroupID
+		}
+		if source.Lang == req.Lang || groupHasLang(groupID, req.Lang, 0) {
+			ctx.JSON(consts.StatusConflict, map[string]interface{}{
+				"code":    409,
+				"message": "翻译分组中已存在该语言的文章: " + req.Lang,
+			})
+			return
+		}
+		translationGroupID = &groupID
+	}
+
 	// 生成文章slug
 	postSlug := req.Slug
 	if postSlug == "" {
 		postSlug = slug.Make(req.Title)
 	}
 
-	// 检查slug是否已存在
-	var existingPost models.Post
-	result := config.DB.Where("slug = ?", postSlug).First(&existingPost)
-	if result.RowsAffected > 0 {
+	// 检查同一语言下slug是否已存在 (包括回收站中的文章)
+	if slugTaken(postSlug, req.Lang, 0) {
 		// 如果slug已存在，添加时间戳后缀
 		postSlug = postSlug + "-" + time.Now().Format("20060102150405")
 	}
 
 	// 创建文章
 	post := models.Post{
-		Title:      req.Title,
-		Slug:       postSlug,
-		Content:    req.Content,
-		Excerpt:    req.Excerpt,
-		CoverImage: req.CoverImage,
-		Status:     req.Status,
-		AuthorID:   uint(userID.(uint)),
+		Title:              req.Title,
+		Slug:               postSlug,
+		Content:            req.Content,
+		Excerpt:            req.Excerpt,
+		CoverImage:         req.CoverImage,
+		SEO:                req.SEO,
+		CommentPolicy:      req.CommentPolicy,
+		Status:             req.Status,
+		Lang:               req.Lang,
+		AuthorID:           uint(userID.(uint)),
+		TranslationGroupID: translationGroupID,
+	}
+	if post.Status == models.PostStatusPendingReview {
+		now := time.Now()
+		post.SubmittedAt = &now
+	}
+	if post.Status == models.PostStatusPublished {
+		now := time.Now()
+		post.PublishedAt = &now
 	}
 
 	// 开始事务
@@ -97,6 +208,20 @@ func CreatePost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
+	// 原文尚未分组时，以原文ID作为分组ID一并写入
+	if translationGroupID != nil {
+		if err := tx.Model(&models.Post{}).Where("id = ?", *translationGroupID).
+			UpdateColumn("translation_group_id", *translationGroupID).Error; err != nil {
+			tx.Rollback()
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "关联翻译失败",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
 	// 处理分类
 	if len(req.Categories) > 0 {
 		var categories []models.Category
@@ -143,13 +268,43 @@ func CreatePost(c context.Context, ctx *app.RequestContext) {
 		}
 	}
 
+	// 处理共同作者
+	if len(req.Coauthors) > 0 {
+		if err := replacePostAuthors(tx, post.ID, req.Coauthors); err != nil {
+			tx.Rollback()
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "设置共同作者失败",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	// 保存自定义字段
+	for key, value := range metaValues {
+		if err := tx.Create(&models.PostMeta{PostID: post.ID, MetaKey: key, Value: value}).Error; err != nil {
+			tx.Rollback()
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "设置自定义字段失败",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
 	// 提交事务
 	tx.Commit()
 
+	// 文章变更后相关文章推荐需要重新计算
+	InvalidateRelatedCache()
+
 	// 加载关联数据
-	config.DB.Preload("Author").Preload("Categories").Preload("Tags").First(&post, post.ID)
+	config.DB.Preload("Author").Scopes(preloadCoauthors).Preload("Categories").Preload("Tags").First(&post, post.ID)
 
 	// 返回文章信息
+	setPostETag(ctx, post)
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
 		"code":    200,
 		"message": "创建文章成功",
@@ -184,18 +339,13 @@ func UpdatePost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
-	// 检查权限（只有作者或管理员可以更新文章）
-	if post.AuthorID != uint(userID.(uint)) {
-		// 检查是否为管理员
-		var user models.User
-		config.DB.First(&user, userID)
-		if user.Role != "admin" {
-			ctx.JSON(consts.StatusForbidden, map[string]interface{}{
-				"code":    403,
-				"message": "无权更新该文章",
-			})
-			return
-		}
+	// 检查权限（只有作者、共同作者或管理员可以更新文章）
+	if !canEditPost(post, userID.(uint)) {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "无权更新该文章",
+		})
+		return
 	}
 
 	// 解析请求体
@@ -209,8 +359,61 @@ func UpdatePost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
-	// 更新文章字段
-	updates := map[string]interface{}{}
+	// 共同作者、slug、语言和评论策略只能由主作者或管理员修改
+	changesOwnerFields := req.Coauthors != nil ||
+		(req.Slug != "" && req.Slug != post.Slug) ||
+		(req.Lang != "" && req.Lang != post.Lang) ||
+		(req.CommentPolicy != "" && req.CommentPolicy != post.CommentPolicy)
+	if changesOwnerFields && post.AuthorID != userID.(uint) && !isAdminRequest(ctx) {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "只有文章主作者或管理员可以修改共同作者、slug、语言和评论策略",
+		})
+		return
+	}
+
+	// 校验共同作者
+	if err := checkPostAuthors(post.AuthorID, req.Coauthors); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// 校验 SEO 元数据
+	if req.SEO != nil {
+		if err := validateSEOMeta(*req.SEO); err != nil {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	// 检查编辑所基于的版本，防止覆盖他人的修改
+	expectedVersion, ok := requestedPostVersion(ctx, post, req.Version)
+	if !ok {
+		setPostETag(ctx, post)
+		ctx.JSON(consts.StatusPreconditionRequired, map[string]interface{}{
+			"code":    428,
+			"message": "缺少版本信息，请通过 If-Match 请求头或 version 字段提供",
+			"data": map[string]interface{}{
+				"current_version": post.Version,
+			},
+		})
+		return
+	}
+	if expectedVersion != post.Version {
+		respondVersionConflict(ctx, post)
+		return
+	}
+
+	// 更新文章字段 (版本号递增)
+	updates := map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	}
 	if req.Title != "" {
 		updates["title"] = req.Title
 	}
@@ -223,17 +426,66 @@ func UpdatePost(c context.Context, ctx *app.RequestContext) {
 	if req.CoverImage != "" {
 		updates["cover_image"] = req.CoverImage
 	}
-	if req.Status != "" {
+	if req.SEO != nil {
+		for column, value := range seoColumns(*req.SEO) {
+			updates[column] = value
+		}
+	}
+	if req.CommentPolicy != "" {
+		if !models.IsValidCommentPolicy(req.CommentPolicy) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "无效的评论策略: " + req.CommentPolicy,
+			})
+			return
+		}
+		updates["comment_policy"] = req.CommentPolicy
+	}
+	if req.Status != "" && req.Status != post.Status {
+		// 按文章状态机校验状态流转，审核结果只能通过审核接口产生，管理员可以直接发布
+		if !models.CanTransitionPostStatus(post.Status, req.Status, isAdminRequest(ctx)) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "不允许将文章状态从 " + post.Status + " 变更为 " + req.Status,
+			})
+			return
+		}
 		updates["status"] = req.Status
+		if req.Status == models.PostStatusPendingReview && post.Status != models.PostStatusPendingReview {
+			updates["submitted_at"] = time.Now()
+		}
+		setPublishedAt(post, req.Status, updates)
+	}
+
+	// 处理语言更新
+	lang := post.Lang
+	if req.Lang != "" && req.Lang != post.Lang {
+		if !isSupportedLang(req.Lang) {
+			ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+				"code":    400,
+				"message": "不支持的语言: " + req.Lang,
+			})
+			return
+		}
+		if post.TranslationGroupID != nil && groupHasLang(*post.TranslationGroupID, req.Lang, post.ID) {
+			ctx.JSON(consts.StatusConflict, map[string]interface{}{
+				"code":    409,
+				"message": "翻译分组中已存在该语言的文章: " + req.Lang,
+			})
+			return
+		}
+		lang = req.Lang
+		updates["lang"] = lang
 	}
 
-	// 处理slug更新
+	// 处理slug更新 (slug在同一语言内唯一)
 	postSlug := req.Slug
-	if postSlug != "" && postSlug != post.Slug {
-		// 检查新slug是否已存在
-		var existingPost models.Post
-		result := config.DB.Where("slug = ? AND id != ?", postSlug, post.ID).First(&existingPost)
-		if result.RowsAffected > 0 {
+	if postSlug == "" && lang != post.Lang {
+		postSlug = post.Slug
+	}
+	if postSlug != "" && (postSlug != post.Slug || lang != post.Lang) {
+		// 检查新slug是否已存在 (包括回收站中的文章)
+		if slugTaken(postSlug, lang, post.ID) {
 			// 如果slug已存在，添加时间戳后缀
 			postSlug = postSlug + "-" + time.Now().Format("20060102150405")
 		}
@@ -243,17 +495,23 @@ func UpdatePost(c context.Context, ctx *app.RequestContext) {
 	// 开始事务
 	tx := config.DB.Begin()
 
-	// 更新文章基本信息
-	if len(updates) > 0 {
-		if err := tx.Model(&post).Updates(updates).Error; err != nil {
-			tx.Rollback()
-			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
-				"code":    500,
-				"message": "更新文章失败",
-				"error":   err.Error(),
-			})
-			return
-		}
+	// 更新文章基本信息，仅当版本未被并发修改时生效
+	result = tx.Model(&post).Where("version = ?", expectedVersion).Updates(updates)
+	if result.Error != nil {
+		tx.Rollback()
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "更新文章失败",
+			"error":   result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		var current models.Post
+		config.DB.First(&current, post.ID)
+		respondVersionConflict(ctx, current)
+		return
 	}
 
 	// 更新分类
@@ -306,13 +564,33 @@ func UpdatePost(c context.Context, ctx *app.RequestContext) {
 		}
 	}
 
+	// 更新共同作者
+	if req.Coauthors != nil {
+		if err := replacePostAuthors(tx, post.ID, req.Coauthors); err != nil {
+			tx.Rollback()
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "更新共同作者失败",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
 	// 提交事务
 	tx.Commit()
 
+	// 文章变更后相关文章推荐需要重新计算
+	InvalidateRelatedCache()
+
 	// 加载更新后的关联数据
-	config.DB.Preload("Author").Preload("Categories").Preload("Tags").First(&post, post.ID)
+	config.DB.Preload("Author").Scopes(preloadCoauthors).Preload("Categories").Preload("Tags").First(&post, post.ID)
+
+	// 附带编辑锁信息，便于编辑器提示其他人正在编辑
+	post.EditLock, _ = activeEditLock(post.ID)
 
 	// 返回更新后的文章信息
+	setPostETag(ctx, post)
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
 		"code":    200,
 		"message": "更新文章成功",
@@ -361,45 +639,10 @@ func DeletePost(c context.Context, ctx *app.RequestContext) {
 		}
 	}
 
-	// 开始事务
-	tx := config.DB.Begin()
-
-	// 删除文章与分类的关联
-	if err := tx.Model(&post).Association("Categories").Clear(); err != nil {
-		tx.Rollback()
-		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
-			"code":    500,
-			"message": "删除文章分类关联失败",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	// 删除文章与标签的关联
-	if err := tx.Model(&post).Association("Tags").Clear(); err != nil {
-		tx.Rollback()
-		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
-			"code":    500,
-			"message": "删除文章标签关联失败",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	// 删除文章的评论
-	if err := tx.Where("post_id = ?", post.ID).Delete(&models.Comment{}).Error; err != nil {
-		tx.Rollback()
-		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
-			"code":    500,
-			"message": "删除文章评论失败",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	// 删除文章
-	if err := tx.Delete(&post).Error; err != nil {
-		tx.Rollback()
+	// 移入回收站：文章及其评论使用相同的删除时间软删除，分类、标签关联保留，便于恢复
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return trashPost(tx, &post)
+	}); err != nil {
 		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
 			"code":    500,
 			"message": "删除文章失败",
@@ -408,12 +651,12 @@ func DeletePost(c context.Context, ctx *app.RequestContext) {
 		return
 	}
 
-	// 提交事务
-	tx.Commit()
+	// 文章变更后相关文章推荐需要重新计算
+	InvalidateRelatedCache()
 
 	// 返回成功信息
 	ctx.JSON(consts.StatusOK, map[string]interface{}{
 		"code":    200,
-		"message": "删除文章成功",
+		"message": "文章已移入回收站",
 	})
 }
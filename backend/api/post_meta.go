@@ -0,0 +1,462 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"gorm.io/gorm"
+)
+
+// metaKeyPattern 自定义字段键名格式
+var metaKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// MetaFieldRequest 自定义字段定义请求
+type MetaFieldRequest struct {
+	Key         string   `json:"key"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	Options     []string `json:"options"`
+	Required    *bool    `json:"required"`
+	Description string   `json:"description"`
+}
+
+// loadMetaFields 加载全部自定义字段定义，以键名索引
+func loadMetaFields() (map[string]models.PostMetaField, error) {
+	var fields []models.PostMetaField
+	if err := config.DB.Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string]models.PostMetaField, len(fields))
+	for _, field := range fields {
+		result[field.Key] = decodeMetaFieldOptions(field)
+	}
+	return result, nil
+}
+
+// decodeMetaFieldOptions 解析 select 类型字段的可选值
+func decodeMetaFieldOptions(field models.PostMetaField) models.PostMetaField {
+	if field.Options != "" {
+		json.Unmarshal([]byte(field.Options), &field.OptionList)
+	}
+	return field
+}
+
+// normalizeMetaValue 按字段类型校验取值，并转换为统一的文本存储格式
+func normalizeMetaValue(field models.PostMetaField, value interface{}) (string, error) {
+	switch field.Type {
+	case models.MetaFieldTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case string:
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return "", fmt.Errorf("字段 %s 必须是数字", field.Key)
+			}
+			return strconv.FormatFloat(n, 'f', -1, 64), nil
+		}
+		return "", fmt.Errorf("字段 %s 必须是数字", field.Key)
+	case models.MetaFieldTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return "", fmt.Errorf("字段 %s 必须是布尔值", field.Key)
+			}
+			return strconv.FormatBool(b), nil
+		}
+		return "", fmt.Errorf("字段 %s 必须是布尔值", field.Key)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("字段 %s 必须是字符串", field.Key)
+	}
+	str = strings.TrimSpace(str)
+	if len(str) > 1000 {
+		return "", fmt.Errorf("字段 %s 长度不能超过1000", field.Key)
+	}
+
+	switch field.Type {
+	case models.MetaFieldTypeString:
+		if len(str) > 255 {
+			return "", fmt.Errorf("字段 %s 长度不能超过255", field.Key)
+		}
+	case models.MetaFieldTypeURL:
+		u, err := url.Parse(str)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("字段 %s 必须是有效的 http(s) 链接", field.Key)
+		}
+	case models.MetaFieldTypeDate:
+		if _, err := time.Parse("2006-01-02", str); err != nil {
+			return "", fmt.Errorf("字段 %s 必须是 YYYY-MM-DD 格式的日期", field.Key)
+		}
+	case models.MetaFieldTypeSelect:
+		for _, option := range field.OptionList {
+			if option == str {
+				return str, nil
+			}
+		}
+		return "", fmt.Errorf("字段 %s 的取值不在可选范围内", field.Key)
+	}
+	return str, nil
+}
+
+// decodeMetaValue 将存储的文本按字段类型转换为响应中的值
+func decodeMetaValue(field models.PostMetaField, value string) interface{} {
+	switch field.Type {
+	case models.MetaFieldTypeNumber:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case models.MetaFieldTypeBoolean:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// fillPostMeta 为文章填充自定义字段值
+func fillPostMeta(posts []models.Post) {
+	if len(posts) == 0 {
+		return
+	}
+
+	postIDs := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	var metas []models.PostMeta
+	if err := config.DB.Where("post_id IN ?", postIDs).Find(&metas).Error; err != nil || len(metas) == 0 {
+		return
+	}
+	fields, err := loadMetaFields()
+	if err != nil {
+		return
+	}
+
+	byPost := make(map[uint]map[string]interface{})
+	for _, meta := range metas {
+		field, ok := fields[meta.MetaKey]
+		if !ok {
+			continue // 字段定义已删除
+		}
+		if byPost[meta.PostID] == nil {
+			byPost[meta.PostID] = make(map[string]interface{})
+		}
+		byPost[meta.PostID][meta.MetaKey] = decodeMetaValue(field, meta.Value)
+	}
+	for i := range posts {
+		posts[i].Meta = byPost[posts[i].ID]
+	}
+}
+
+// metaFilterScope 按查询参数 meta.<key>=<value> 过滤文章
+func metaFilterScope(ctx *app.RequestContext) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		filters := make(map[string]string)
+		ctx.QueryArgs().VisitAll(func(key, value []byte) {
+			if k := string(key); strings.HasPrefix(k, "meta.") {
+				filters[strings.TrimPrefix(k, "meta.")] = string(value)
+			}
+		})
+		if len(filters) == 0 {
+			return db
+		}
+
+		fields, err := loadMetaFields()
+		if err != nil {
+			return db
+		}
+		for key, raw := range filters {
+			field, ok := fields[key]
+			if !ok {
+				// 未定义的字段无法匹配任何文章
+				db = db.Where("1 = 0")
+				continue
+			}
+			value, err := normalizeMetaValue(field, raw)
+			if err != nil {
+				db = db.Where("1 = 0")
+				continue
+			}
+			db = db.Where("post.id IN (?)", config.DB.Model(&models.PostMeta{}).
+				Select("post_id").Where("meta_key = ? AND value = ?", key, value))
+		}
+		return db
+	}
+}
+
+// GetMetaFields 获取自定义字段定义列表
+func GetMetaFields(c context.Context, ctx *app.RequestContext) {
+	var fields []models.PostMetaField
+	if err := config.DB.Order("id ASC").Find(&fields).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "获取自定义字段失败", "error": err.Error()})
+		return
+	}
+	for i := range fields {
+		fields[i] = decodeMetaFieldOptions(fields[i])
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{"code": 200, "message": "获取自定义字段成功", "data": fields})
+}
+
+// CreateMetaField 创建自定义字段定义
+func CreateMetaField(c context.Context, ctx *app.RequestContext) {
+	var req MetaFieldRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
+		return
+	}
+	if !metaKeyPattern.MatchString(req.Key) {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "字段键名只能包含小写字母、数字和下划线，且以字母开头"})
+		return
+	}
+	if req.Type == "" {
+		req.Type = models.MetaFieldTypeString
+	}
+
+	field := models.PostMetaField{
+		Key:         req.Key,
+		Label:       req.Label,
+		Type:        req.Type,
+		Description: req.Description,
+		Required:    req.Required != nil && *req.Required,
+	}
+	if msg := applyMetaFieldOptions(&field, req.Options); msg != "" {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": msg})
+		return
+	}
+
+	if err := config.DB.Create(&field).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "创建自定义字段失败", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, map[string]interface{}{"code": 201, "message": "创建自定义字段成功", "data": decodeMetaFieldOptions(field)})
+}
+
+// UpdateMetaField 更新自定义字段定义 (键名和类型创建后不可修改)
+func UpdateMetaField(c context.Context, ctx *app.RequestContext) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "无效的字段ID"})
+		return
+	}
+
+	var req MetaFieldRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
+		return
+	}
+
+	var field models.PostMetaField
+	if err := config.DB.First(&field, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, map[string]interface{}{"code": 404, "message": "自定义字段不存在"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "查询自定义字段失败", "error": err.Error()})
+		}
+		return
+	}
+	if (req.Key != "" && req.Key != field.Key) || (req.Type != "" && req.Type != field.Type) {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "字段键名和类型创建后不可修改"})
+		return
+	}
+
+	if req.Label != "" {
+		field.Label = req.Label
+	}
+	if req.Description != "" {
+		field.Description = req.Description
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.Options != nil {
+		if msg := applyMetaFieldOptions(&field, req.Options); msg != "" {
+			ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": msg})
+			return
+		}
+	}
+
+	if err := config.DB.Save(&field).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "更新自定义字段失败", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"code": 200, "message": "更新自定义字段成功", "data": decodeMetaFieldOptions(field)})
+}
+
+// DeleteMetaField 删除自定义字段定义及所有文章中的对应取值
+func DeleteMetaField(c context.Context, ctx *app.RequestContext) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "无效的字段ID"})
+		return
+	}
+
+	var field models.PostMetaField
+	if err := config.DB.First(&field, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, map[string]interface{}{"code": 404, "message": "自定义字段不存在"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "查询自定义字段失败", "error": err.Error()})
+		}
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("meta_key = ?", field.Key).Delete(&models.PostMeta{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&field).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "删除自定义字段失败", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"code": 200, "message": "删除自定义字段成功"})
+}
+
+// SetPostMeta 设置文章的自定义字段值，值为 null 时删除该字段，未提及的字段保持不变
+func SetPostMeta(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req map[string]interface{}
+	if err := json.Unmarshal(ctx.Request.Body(), &req); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "请求参数错误", "error": err.Error()})
+		return
+	}
+
+	fields, err := loadMetaFields()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "获取自定义字段失败", "error": err.Error()})
+		return
+	}
+
+	var existing []models.PostMeta
+	config.DB.Where("post_id = ?", post.ID).Find(&existing)
+	present := make(map[string]bool, len(existing))
+	for _, meta := range existing {
+		present[meta.MetaKey] = true
+	}
+
+	// 校验并转换所有取值
+	values := make(map[string]string)
+	var removed []string
+	for key, value := range req {
+		field, ok := fields[key]
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "未定义的自定义字段: " + key})
+			return
+		}
+		if value == nil {
+			removed = append(removed, key)
+			present[key] = false
+			continue
+		}
+		normalized, err := normalizeMetaValue(field, value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": err.Error()})
+			return
+		}
+		values[key] = normalized
+		present[key] = true
+	}
+	for key, field := range fields {
+		if field.Required && !present[key] {
+			ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "缺少必填的自定义字段: " + key})
+			return
+		}
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if len(removed) > 0 {
+			if err := tx.Where("post_id = ? AND meta_key IN ?", post.ID, removed).Delete(&models.PostMeta{}).Error; err != nil {
+				return err
+			}
+		}
+		for key, value := range values {
+			meta := models.PostMeta{PostID: post.ID, MetaKey: key}
+			if err := tx.Where(meta).Assign(models.PostMeta{Value: value}).FirstOrCreate(&meta).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "设置自定义字段失败", "error": err.Error()})
+		return
+	}
+
+	posts := []models.Post{post}
+	fillPostMeta(posts)
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"code": 200, "message": "设置自定义字段成功", "data": posts[0].Meta})
+}
+
+// newPostMetaValues 校验新文章的自定义字段值并转换为存储格式，必填字段必须提供
+func newPostMetaValues(input map[string]interface{}) (map[string]string, error) {
+	fields, err := loadMetaFields()
+	if err != nil {
+		return nil, fmt.Errorf("获取自定义字段失败: %w", err)
+	}
+
+	values := make(map[string]string, len(input))
+	for key, value := range input {
+		field, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("未定义的自定义字段: %s", key)
+		}
+		if value == nil {
+			continue
+		}
+		normalized, err := normalizeMetaValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = normalized
+	}
+	for key, field := range fields {
+		if _, ok := values[key]; field.Required && !ok {
+			return nil, fmt.Errorf("缺少必填的自定义字段: %s", key)
+		}
+	}
+	return values, nil
+}
+
+// applyMetaFieldOptions 校验字段类型并写入 select 类型的可选值，返回错误信息
+func applyMetaFieldOptions(field *models.PostMetaField, options []string) string {
+	switch field.Type {
+	case models.MetaFieldTypeString, models.MetaFieldTypeText, models.MetaFieldTypeNumber,
+		models.MetaFieldTypeBoolean, models.MetaFieldTypeURL, models.MetaFieldTypeDate:
+		field.Options = ""
+		return ""
+	case models.MetaFieldTypeSelect:
+		if len(options) == 0 {
+			return "select 类型的字段必须提供可选值"
+		}
+		data, _ := json.Marshal(options)
+		field.Options = string(data)
+		return ""
+	}
+	return "不支持的字段类型: " + field.Type
+}
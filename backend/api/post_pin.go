@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// pinnedFirstOrder 置顶文章优先的排序规则
+const pinnedFirstOrder = "post.is_pinned DESC, post.pin_order ASC, post.created_at DESC"
+
+// PinPostRequest 置顶文章请求
+type PinPostRequest struct {
+	Pinned   bool `json:"pinned"`
+	PinOrder *int `json:"pin_order"` // 为空时追加到置顶列表末尾
+}
+
+// FeaturePostRequest 精选文章请求
+type FeaturePostRequest struct {
+	Featured      bool       `json:"featured"`
+	FeaturedUntil *time.Time `json:"featured_until"` // 为空表示长期精选
+}
+
+// ReorderPinnedRequest 调整置顶顺序请求
+type ReorderPinnedRequest struct {
+	PostIDs []uint `json:"post_ids"` // 按期望顺序排列的置顶文章ID
+}
+
+// activeFeaturedScope 筛选当前有效且已发布的精选文章
+func activeFeaturedScope(db *gorm.DB) *gorm.DB {
+	return db.Where("post.status = ? AND post.is_featured = ? AND (post.featured_until IS NULL OR post.featured_until > ?)",
+		models.PostStatusPublished, true, time.Now())
+}
+
+// PinPost 置顶或取消置顶文章
+func PinPost(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req PinPostRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"is_pinned": req.Pinned,
+		"pin_order": 0,
+	}
+	if req.Pinned {
+		if req.PinOrder != nil {
+			updates["pin_order"] = *req.PinOrder
+		} else {
+			// 未指定顺序时排在已置顶文章之后
+			var maxOrder int
+			if err := config.DB.Model(&models.Post{}).Where("is_pinned = ? AND id != ?", true, post.ID).
+				Select("COALESCE(MAX(pin_order), 0)").Scan(&maxOrder).Error; err != nil {
+				ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+					"code":    500,
+					"message": "查询置顶顺序失败",
+					"error":   err.Error(),
+				})
+				return
+			}
+			updates["pin_order"] = maxOrder + 1
+		}
+	}
+
+	if err := config.DB.Model(&post).Updates(updates).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "更新置顶状态失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	config.DB.First(&post, post.ID)
+
+	message := "取消置顶成功"
+	if req.Pinned {
+		message = "置顶文章成功"
+	}
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": message,
+		"data":    post,
+	})
+}
+
+// FeaturePost 设置或取消精选文章
+func FeaturePost(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req FeaturePostRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if req.Featured && req.FeaturedUntil != nil && !req.FeaturedUntil.After(time.Now()) {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "精选到期时间必须晚于当前时间",
+		})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"is_featured":    req.Featured,
+		"featured_until": nil,
+	}
+	if req.Featured && req.FeaturedUntil != nil {
+		updates["featured_until"] = *req.FeaturedUntil
+	}
+
+	if err := config.DB.Model(&post).Updates(updates).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "更新精选状态失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	config.DB.First(&post, post.ID)
+
+	message := "取消精选成功"
+	if req.Featured {
+		message = "设置精选成功"
+	}
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": message,
+		"data":    post,
+	})
+}
+
+// ReorderPinnedPosts 按给定顺序重排置顶文章
+func ReorderPinnedPosts(c context.Context, ctx *app.RequestContext) {
+	var req ReorderPinnedRequest
+	if err := ctx.BindAndValidate(&req); err != nil || len(req.PostIDs) == 0 {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误，post_ids 不能为空",
+		})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for i, postID := range req.PostIDs {
+			if err := tx.Model(&models.Post{}).Where("id = ? AND is_pinned = ?", postID, true).
+				Update("pin_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "调整置顶顺序失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var posts []models.Post
+	if err := config.DB.Where("is_pinned = ? AND status = ?", true, models.PostStatusPublished).Order(pinnedFirstOrder).Find(&posts).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取置顶文章失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "调整置顶顺序成功",
+		"data":    posts,
+	})
+}
+
+// findPostByParam 按路由参数查询文章，失败时直接写入错误响应
+func findPostByParam(ctx *app.RequestContext) (models.Post, bool) {
+	var post models.Post
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的文章ID",
+		})
+		return post, false
+	}
+
+	if err := config.DB.First(&post, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+				"code":    404,
+				"message": "文章不存在",
+			})
+		} else {
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "查询文章失败",
+				"error":   err.Error(),
+			})
+		}
+		return post, false
+	}
+	return post, true
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/alvinhmg/blog/utils"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// 相关文章评分权重
+const (
+	relatedCategoryWeight = 3.0  // 每个共同分类的得分
+	relatedTagWeight      = 2.0  // 每个共同标签的得分
+	relatedTextWeight     = 5.0  // 文本相似度 (0~1) 的得分倍数
+	relatedTitleRepeat    = 3    // 标题词项的加权次数
+	relatedContentRunes   = 2000 // 参与相似度计算的正文最大字符数
+	relatedCacheTTL       = 10 * time.Minute
+)
+
+// RelatedPost 相关文章及其得分
+type RelatedPost struct {
+	models.Post
+	Score float64 `json:"score"`
+}
+
+type relatedCacheEntry struct {
+	posts     []RelatedPost
+	expiresAt time.Time
+}
+
+// relatedCache 相关文章结果缓存，任何文章变更时整体失效
+var relatedCache = struct {
+	sync.RWMutex
+	entries map[string]relatedCacheEntry
+}{entries: make(map[string]relatedCacheEntry)}
+
+// InvalidateRelatedCache 清空相关文章缓存 (文章创建、更新、删除后调用)
+func InvalidateRelatedCache() {
+	relatedCache.Lock()
+	relatedCache.entries = make(map[string]relatedCacheEntry)
+	relatedCache.Unlock()
+}
+
+// GetRelatedPosts 获取相关文章推荐
+func GetRelatedPosts(c context.Context, ctx *app.RequestContext) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的文章ID",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "5"))
+	if err != nil || limit < 1 {
+		limit = 5
+	}
+	if limit > 20 {
+		limit = 20
+	}
+
+	// 以解析后的文章ID作为缓存键，避免 "01" 与 "1" 等不同写法各占一份缓存
+	cacheKey := strconv.Itoa(id) + ":" + strconv.Itoa(limit)
+	relatedCache.RLock()
+	entry, ok := relatedCache.entries[cacheKey]
+	relatedCache.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		ctx.JSON(consts.StatusOK, map[string]interface{}{
+			"code":    200,
+			"message": "获取相关文章成功",
+			"data":    entry.posts,
+		})
+		return
+	}
+
+	// 查询当前文章 (只为已发布的文章推荐)
+	var post models.Post
+	if err := config.DB.Preload("Categories").Preload("Tags").Where("status = ?", models.PostStatusPublished).First(&post, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+				"code":    404,
+				"message": "文章不存在",
+			})
+		} else {
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "查询文章失败",
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	// 查询候选文章 (其他已发布文章)
+	var candidates []models.Post
+	if err := config.DB.Preload("Author").Preload("Categories").Preload("Tags").
+		Where("status = ? AND id != ?", "published", post.ID).
+		Find(&candidates).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取相关文章失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	related := rankRelatedPosts(post, candidates, limit)
+
+	relatedCache.Lock()
+	relatedCache.entries[cacheKey] = relatedCacheEntry{
+		posts:     related,
+		expiresAt: time.Now().Add(relatedCacheTTL),
+	}
+	relatedCache.Unlock()
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取相关文章成功",
+		"data":    related,
+	})
+}
+
+// rankRelatedPosts 按共同分类、共同标签和文本相似度为候选文章打分，返回得分最高的若干篇
+func rankRelatedPosts(post models.Post, candidates []models.Post, limit int) []RelatedPost {
+	categoryIDs := make(map[uint]bool, len(post.Categories))
+	for _, category := range post.Categories {
+		categoryIDs[category.ID] = true
+	}
+	tagIDs := make(map[uint]bool, len(post.Tags))
+	for _, tag := range post.Tags {
+		tagIDs[tag.ID] = true
+	}
+	vector := postTermVector(post)
+
+	related := make([]RelatedPost, 0, len(candidates))
+	for _, candidate := range candidates {
+		score := 0.0
+		for _, category := range candidate.Categories {
+			if categoryIDs[category.ID] {
+				score += relatedCategoryWeight
+			}
+		}
+		for _, tag := range candidate.Tags {
+			if tagIDs[tag.ID] {
+				score += relatedTagWeight
+			}
+		}
+		score += relatedTextWeight * utils.CosineSimilarity(vector, postTermVector(candidate))
+		if score <= 0 {
+			continue
+		}
+
+		// 列表中不返回正文，减少响应体积
+		candidate.Content = ""
+		related = append(related, RelatedPost{Post: candidate, Score: score})
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].Score == related[j].Score {
+			return related[i].CreatedAt.After(related[j].CreatedAt)
+		}
+		return related[i].Score > related[j].Score
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related
+}
+
+// postTermVector 生成文章标题和正文的词频向量，标题词项加权
+func postTermVector(post models.Post) map[string]float64 {
+	content := []rune(post.Content)
+	if len(content) > relatedContentRunes {
+		content = content[:relatedContentRunes]
+	}
+
+	tokens := utils.Tokenize(string(content))
+	titleTokens := utils.Tokenize(post.Title)
+	for i := 0; i < relatedTitleRepeat; i++ {
+		tokens = append(tokens, titleTokens...)
+	}
+	return utils.TermFrequency(tokens)
+}
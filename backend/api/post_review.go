@@ -0,0 +1,345 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// SubmitReviewRequest 提交审核请求
+type SubmitReviewRequest struct {
+	ReviewerID *uint `json:"reviewer_id"` // 可选，指定审核人
+}
+
+// AssignReviewerRequest 指定审核人请求
+type AssignReviewerRequest struct {
+	ReviewerID uint `json:"reviewer_id"`
+}
+
+// ReviewNoteRequest 审核意见请求
+type ReviewNoteRequest struct {
+	Content string `json:"content"`
+	Quote   string `json:"quote"`  // 批注针对的原文片段
+	Offset  *int   `json:"offset"` // 原文片段在正文中的起始位置
+}
+
+// ReviewDecisionRequest 审核结论请求 (可附带一条审核意见)
+type ReviewDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+// SubmitPostForReview 提交文章审核
+func SubmitPostForReview(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req SubmitReviewRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"submitted_at": time.Now(),
+	}
+	if req.ReviewerID != nil {
+		if !checkReviewer(ctx, post, *req.ReviewerID) {
+			return
+		}
+		updates["reviewer_id"] = *req.ReviewerID
+	}
+
+	changePostStatus(ctx, post, models.PostStatusPendingReview, false, updates, "", "已提交审核")
+}
+
+// AssignPostReviewer 指定文章审核人
+func AssignPostReviewer(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req AssignReviewerRequest
+	if err := ctx.BindAndValidate(&req); err != nil || req.ReviewerID == 0 {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误，reviewer_id 不能为空",
+		})
+		return
+	}
+	if !checkReviewer(ctx, post, req.ReviewerID) {
+		return
+	}
+
+	if err := config.DB.Model(&post).Update("reviewer_id", req.ReviewerID).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "指定审核人失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.Preload("Author").Preload("Reviewer").First(&post, post.ID)
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "指定审核人成功",
+		"data":    post,
+	})
+}
+
+// ApprovePost 审核通过并发布文章
+func ApprovePost(c context.Context, ctx *app.RequestContext) {
+	decidePostReview(ctx, models.PostStatusPublished, "审核通过，文章已发布")
+}
+
+// RequestPostChanges 审核要求修改
+func RequestPostChanges(c context.Context, ctx *app.RequestContext) {
+	decidePostReview(ctx, models.PostStatusChangesRequested, "已要求作者修改")
+}
+
+// GetPostReviewNotes 获取文章的审核意见列表
+func GetPostReviewNotes(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	db := config.DB.Preload("User").Where("post_id = ?", post.ID)
+	if resolved := ctx.Query("resolved"); resolved != "" {
+		db = db.Where("resolved = ?", resolved == "true")
+	}
+
+	var notes []models.PostReviewNote
+	if err := db.Order("created_at ASC").Find(&notes).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取审核意见失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取审核意见成功",
+		"data":    notes,
+	})
+}
+
+// AddPostReviewNote 添加审核意见 (支持针对原文片段的行内批注)
+func AddPostReviewNote(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	var req ReviewNoteRequest
+	if err := ctx.BindAndValidate(&req); err != nil || req.Content == "" {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "审核意见内容不能为空",
+		})
+		return
+	}
+
+	note := models.PostReviewNote{
+		PostID:  post.ID,
+		UserID:  userID.(uint),
+		Content: req.Content,
+		Quote:   req.Quote,
+		Offset:  req.Offset,
+	}
+	if err := config.DB.Create(&note).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "添加审核意见失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.Preload("User").First(&note, note.ID)
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "添加审核意见成功",
+		"data":    note,
+	})
+}
+
+// ResolvePostReviewNote 将审核意见标记为已处理
+func ResolvePostReviewNote(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	noteID, err := strconv.Atoi(ctx.Param("noteId"))
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的审核意见ID",
+		})
+		return
+	}
+
+	var note models.PostReviewNote
+	if err := config.DB.Where("post_id = ?", post.ID).First(&note, noteID).Error; err != nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "审核意见不存在",
+		})
+		return
+	}
+
+	if err := config.DB.Model(&note).Update("resolved", true).Error; err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "更新审核意见失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "审核意见已标记为处理",
+		"data":    note,
+	})
+}
+
+// decidePostReview 审核人给出审核结论，并可附带一条审核意见
+func decidePostReview(ctx *app.RequestContext, status string, message string) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	// 指定了审核人时只有审核人可以审核，否则任何非作者的管理员都可以审核
+	if post.ReviewerID != nil && *post.ReviewerID != userID.(uint) {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "只有指定的审核人可以审核该文章",
+		})
+		return
+	}
+	if post.ReviewerID == nil && post.AuthorID == userID.(uint) {
+		ctx.JSON(consts.StatusForbidden, map[string]interface{}{
+			"code":    403,
+			"message": "不能审核自己的文章",
+		})
+		return
+	}
+
+	var req ReviewDecisionRequest
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	changePostStatus(ctx, post, status, true, map[string]interface{}{}, req.Note, message)
+}
+
+// changePostStatus 按状态机修改文章状态并递增版本号，note 不为空时同时记录一条审核意见
+func changePostStatus(ctx *app.RequestContext, post models.Post, status string, byReview bool, updates map[string]interface{}, note string, message string) {
+	if !models.CanTransitionPostStatus(post.Status, status, byReview) {
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": "不允许将文章状态从 " + post.Status + " 变更为 " + status,
+		})
+		return
+	}
+	userID, _ := ctx.Get("userID")
+
+	updates["status"] = status
+	updates["version"] = gorm.Expr("version + 1")
+	setPublishedAt(post, status, updates)
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&post).Updates(updates).Error; err != nil {
+			return err
+		}
+		if note == "" {
+			return nil
+		}
+		return tx.Create(&models.PostReviewNote{
+			PostID:  post.ID,
+			UserID:  userID.(uint),
+			Content: note,
+		}).Error
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "更新文章状态失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if status == models.PostStatusPublished {
+		InvalidateRelatedCache()
+	}
+
+	config.DB.Preload("Author").Preload("Reviewer").Preload("Categories").Preload("Tags").First(&post, post.ID)
+
+	setPostETag(ctx, post)
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": message,
+		"data":    post,
+	})
+}
+
+// isAdminRequest 当前登录用户是否为管理员，管理员修改文章状态时视同审核操作，可以直接发布
+func isAdminRequest(ctx *app.RequestContext) bool {
+	user, exists := ctx.Get("user")
+	return exists && user.(models.User).Role == "admin"
+}
+
+// setPublishedAt 文章首次发布时记录发布时间
+func setPublishedAt(post models.Post, status string, updates map[string]interface{}) {
+	if status == models.PostStatusPublished && post.PublishedAt == nil {
+		updates["published_at"] = time.Now()
+	}
+}
+
+// checkReviewer 检查审核人是否为有效的管理员且不是文章作者，失败时直接写入错误响应
+func checkReviewer(ctx *app.RequestContext, post models.Post, reviewerID uint) bool {
+	if reviewerID == post.AuthorID {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "审核人不能是文章作者",
+		})
+		return false
+	}
+
+	var reviewer models.User
+	if err := config.DB.First(&reviewer, reviewerID).Error; err != nil || reviewer.Role != "admin" {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "审核人不存在或没有审核权限",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// LinkTranslationRequest 关联翻译请求
+type LinkTranslationRequest struct {
+	PostID uint `json:"post_id"` // 要加入当前文章翻译分组的文章ID
+}
+
+// supportedLangs 站点支持的语言列表
+func supportedLangs() []string {
+	return strings.Split(config.GetEnv("SUPPORTED_LANGS", "zh,en"), ",")
+}
+
+// defaultLang 默认语言
+func defaultLang() string {
+	return config.GetEnv("DEFAULT_LANG", "zh")
+}
+
+// isSupportedLang 判断语言代码是否受支持
+func isSupportedLang(lang string) bool {
+	for _, supported := range supportedLangs() {
+		if strings.TrimSpace(supported) == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// langScope 按请求中的 lang 参数过滤文章，未指定时不过滤
+func langScope(ctx *app.RequestContext) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if lang := ctx.Query("lang"); lang != "" {
+			return db.Where("post.lang = ?", lang)
+		}
+		return db
+	}
+}
+
+// slugTaken 判断同一语言下slug是否已被其他文章占用 (包括回收站中的文章)
+func slugTaken(slug, lang string, excludeID uint) bool {
+	var count int64
+	config.DB.Unscoped().Model(&models.Post{}).
+		Where("slug = ? AND lang = ? AND id != ?", slug, lang, excludeID).
+		Count(&count)
+	return count > 0
+}
+
+// groupHasLang 判断翻译分组中是否已有该语言的其他文章
+func groupHasLang(groupID uint, lang string, excludeID uint) bool {
+	var count int64
+	config.DB.Model(&models.Post{}).
+		Where("translation_group_id = ? AND lang = ? AND id != ?", groupID, lang, excludeID).
+		Count(&count)
+	return count > 0
+}
+
+// loadTranslations 加载文章已发布的其他语言版本
+func loadTranslations(post models.Post) ([]models.PostTranslation, error) {
+	if post.TranslationGroupID == nil {
+		return nil, nil
+	}
+
+	var translations []models.PostTranslation
+	err := config.DB.Model(&models.Post{}).
+		Select("id, lang, title, slug").
+		Where("translation_group_id = ? AND id != ? AND status = ?", *post.TranslationGroupID, post.ID, models.PostStatusPublished).
+		Order("lang ASC").
+		Scan(&translations).Error
+	return translations, err
+}
+
+// LinkPostTranslation 将另一篇文章关联为当前文章的翻译
+func LinkPostTranslation(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req LinkTranslationRequest
+	if err := ctx.BindAndValidate(&req); err != nil || req.PostID == 0 || req.PostID == post.ID {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "请求参数错误，post_id 无效",
+		})
+		return
+	}
+
+	var translation models.Post
+	if err := config.DB.First(&translation, req.PostID).Error; err != nil {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "要关联的文章不存在",
+		})
+		return
+	}
+
+	// 当前文章尚未分组时以其ID作为分组ID
+	groupID := post.ID
+	if post.TranslationGroupID != nil {
+		groupID = *post.TranslationGroupID
+	}
+	if translation.TranslationGroupID != nil && *translation.TranslationGroupID != groupID {
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": "要关联的文章已属于其他翻译分组，请先解除关联",
+		})
+		return
+	}
+	if translation.Lang == post.Lang || groupHasLang(groupID, translation.Lang, translation.ID) {
+		ctx.JSON(consts.StatusConflict, map[string]interface{}{
+			"code":    409,
+			"message": "翻译分组中已存在该语言的文章: " + translation.Lang,
+		})
+		return
+	}
+
+	err := config.DB.Model(&models.Post{}).Where("id IN ?", []uint{post.ID, translation.ID}).
+		UpdateColumn("translation_group_id", groupID).Error
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "关联翻译失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.First(&post, post.ID)
+	var translations []models.PostTranslation
+	config.DB.Model(&models.Post{}).Select("id, lang, title, slug").
+		Where("translation_group_id = ? AND id != ?", groupID, post.ID).Scan(&translations)
+	post.Translations = translations
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "关联翻译成功",
+		"data":    post,
+	})
+}
+
+// UnlinkPostTranslation 将文章移出其翻译分组
+func UnlinkPostTranslation(c context.Context, ctx *app.RequestContext) {
+	post, ok := findPostByParam(ctx)
+	if !ok {
+		return
+	}
+	if post.TranslationGroupID == nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "该文章不属于任何翻译分组",
+		})
+		return
+	}
+
+	groupID := *post.TranslationGroupID
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&post).UpdateColumn("translation_group_id", nil).Error; err != nil {
+			return err
+		}
+		// 分组中只剩一篇文章时一并解散
+		var remaining int64
+		tx.Model(&models.Post{}).Where("translation_group_id = ?", groupID).Count(&remaining)
+		if remaining == 1 {
+			return tx.Model(&models.Post{}).Where("translation_group_id = ?", groupID).
+				UpdateColumn("translation_group_id", nil).Error
+		}
+		// 分组ID即该文章ID时，其余文章改用其中最小的文章ID作为新的分组ID，
+		// 避免之后再关联该文章时误并入原分组
+		if remaining == 0 || groupID != post.ID {
+			return nil
+		}
+		var newGroupID uint
+		if err := tx.Unscoped().Model(&models.Post{}).Select("MIN(id)").
+			Where("translation_group_id = ?", groupID).Scan(&newGroupID).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Post{}).Where("translation_group_id = ?", groupID).
+			UpdateColumn("translation_group_id", newGroupID).Error
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "解除翻译关联失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "解除翻译关联成功",
+	})
+}
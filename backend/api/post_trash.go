@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// TrashedPost 回收站中的文章
+type TrashedPost struct {
+	models.Post
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// trashPost 将文章及其评论以相同的删除时间软删除
+func trashPost(tx *gorm.DB, post *models.Post) error {
+	// 截断到毫秒，与数据库 datetime(3) 精度一致，恢复时按该时间精确匹配评论
+	now := time.Now().Truncate(time.Millisecond)
+	if err := tx.Model(&models.Comment{}).Where("post_id = ?", post.ID).
+		UpdateColumn("deleted_at", now).Error; err != nil {
+		return err
+	}
+	return tx.Model(post).UpdateColumn("deleted_at", now).Error
+}
+
+// purgePost 永久删除文章及其所有关联数据
+func purgePost(tx *gorm.DB, post *models.Post) error {
+	if err := tx.Model(post).Association("Categories").Clear(); err != nil {
+		return err
+	}
+	if err := tx.Model(post).Association("Tags").Clear(); err != nil {
+		return err
+	}
+	commentIDs := tx.Unscoped().Model(&models.Comment{}).Select("id").Where("post_id = ?", post.ID)
+	if err := tx.Where("comment_id IN (?)", commentIDs).Delete(&models.CommentRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("comment_id IN (?)", commentIDs).Delete(&models.CommentReaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("comment_id IN (?)", commentIDs).Delete(&models.CommentReport{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("post_id = ?", post.ID).Delete(&models.Comment{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostLike{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostAuthor{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.SeriesPost{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostEditLock{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostAutosave{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostReviewNote{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostMeta{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(post).Error
+}
+
+// findTrashedPost 按路由参数查询回收站中的文章，失败时直接写入错误响应
+func findTrashedPost(ctx *app.RequestContext) (models.Post, bool) {
+	var post models.Post
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的文章ID",
+		})
+		return post, false
+	}
+
+	if err := config.DB.Unscoped().Where("deleted_at IS NOT NULL").First(&post, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+				"code":    404,
+				"message": "回收站中不存在该文章",
+			})
+		} else {
+			ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+				"code":    500,
+				"message": "查询文章失败",
+				"error":   err.Error(),
+			})
+		}
+		return post, false
+	}
+	return post, true
+}
+
+// GetTrashedPosts 获取回收站文章列表
+func GetTrashedPosts(c context.Context, ctx *app.RequestContext) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	db := config.DB.Unscoped().Model(&models.Post{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	db.Count(&total)
+
+	var posts []models.Post
+	result := db.Preload("Author").Preload("Categories").Preload("Tags").
+		Order("deleted_at DESC").Limit(pageSize).Offset((page - 1) * pageSize).Find(&posts)
+	if result.Error != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取回收站文章失败",
+			"error":   result.Error.Error(),
+		})
+		return
+	}
+
+	items := make([]TrashedPost, 0, len(posts))
+	for _, post := range posts {
+		post.Content = ""
+		items = append(items, TrashedPost{Post: post, DeletedAt: post.DeletedAt.Time})
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取回收站文章成功",
+		"data": map[string]interface{}{
+			"posts":          items,
+			"total":          total,
+			"page":           page,
+			"page_size":      pageSize,
+			"total_page":     (total + int64(pageSize) - 1) / int64(pageSize),
+			"retention_days": int(trashRetention().Hours() / 24),
+		},
+	})
+}
+
+// RestorePost 从回收站恢复文章及随文章一同删除的评论
+func RestorePost(c context.Context, ctx *app.RequestContext) {
+	post, ok := findTrashedPost(ctx)
+	if !ok {
+		return
+	}
+
+	deletedAt := post.DeletedAt.Time
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// 只恢复与文章同时删除的评论，之前单独删除的评论保持删除状态
+		if err := tx.Unscoped().Model(&models.Comment{}).
+			Where("post_id = ? AND deleted_at = ?", post.ID, deletedAt).
+			UpdateColumn("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&post).UpdateColumn("deleted_at", nil).Error
+	})
+	if err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "恢复文章失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	InvalidateRelatedCache()
+
+	config.DB.Preload("Author").Preload("Categories").Preload("Tags").First(&post, post.ID)
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "恢复文章成功",
+		"data":    post,
+	})
+}
+
+// PurgePost 永久删除回收站中的文章
+func PurgePost(c context.Context, ctx *app.RequestContext) {
+	post, ok := findTrashedPost(ctx)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return purgePost(tx, &post)
+	}); err != nil {
+		ctx.JSON(consts.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "永久删除文章失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "永久删除文章成功",
+	})
+}
+
+// trashRetention 回收站保留时长，配置为非正数时使用默认值，避免清理任务删除回收站中的所有文章
+func trashRetention() time.Duration {
+	const defaultRetention = 30 * 24 * time.Hour
+	retention := config.GetEnvDuration("TRASH_RETENTION", defaultRetention)
+	if retention <= 0 {
+		log.Printf("TRASH_RETENTION 配置无效 (%s)，使用默认值 %s", retention, defaultRetention)
+		return defaultRetention
+	}
+	return retention
+}
+
+// StartTrashPurger 启动回收站定时清理任务，永久删除超过保留时长的文章
+func StartTrashPurger(ctx context.Context) {
+	interval := config.GetEnvDuration("TRASH_PURGE_INTERVAL", time.Hour)
+	if interval <= 0 {
+		// time.NewTicker 不接受非正数的间隔，配置无效时使用默认值
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			PurgeExpiredTrash()
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// PurgeExpiredTrash 永久删除回收站中超过保留时长的文章
+func PurgeExpiredTrash() {
+	var posts []models.Post
+	cutoff := time.Now().Add(-trashRetention())
+	if err := config.DB.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Find(&posts).Error; err != nil {
+		log.Println("查询过期回收站文章失败:", err)
+		return
+	}
+
+	for i := range posts {
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return purgePost(tx, &posts[i])
+		}); err != nil {
+			log.Printf("永久删除文章 %d 失败: %v", posts[i].ID, err)
+		}
+	}
+	if len(posts) > 0 {
+		log.Printf("回收站清理完成，永久删除 %d 篇文章", len(posts))
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// postETag 根据文章ID和版本号生成 ETag
+func postETag(post models.Post) string {
+	return fmt.Sprintf(`"post-%d-v%d"`, post.ID, post.Version)
+}
+
+// setPostETag 在响应头中写入文章的 ETag
+func setPostETag(ctx *app.RequestContext, post models.Post) {
+	ctx.Response.Header.Set("ETag", postETag(post))
+}
+
+// requestedPostVersion 从 If-Match 请求头或请求体的 version 字段获取客户端基于的文章版本，
+// If-Match: * 表示接受任意当前版本
+func requestedPostVersion(ctx *app.RequestContext, post models.Post, bodyVersion *int) (int, bool) {
+	if ifMatch := strings.TrimSpace(string(ctx.GetHeader("If-Match"))); ifMatch != "" {
+		ifMatch = strings.TrimPrefix(ifMatch, "W/")
+		if ifMatch == "*" || ifMatch == postETag(post) {
+			return post.Version, true
+		}
+		// 兼容直接传入版本号的写法，如 If-Match: "3"
+		version, err := strconv.Atoi(strings.Trim(ifMatch, `"`))
+		if err != nil {
+			return 0, true // 无法识别的 ETag 视为版本不匹配
+		}
+		return version, true
+	}
+	if bodyVersion != nil {
+		return *bodyVersion, true
+	}
+	return 0, false
+}
+
+// respondVersionConflict 返回版本冲突响应，附带服务器上的最新版本
+func respondVersionConflict(ctx *app.RequestContext, current models.Post) {
+	setPostETag(ctx, current)
+	ctx.JSON(consts.StatusConflict, map[string]interface{}{
+		"code":    409,
+		"message": "文章已被其他人修改，请基于最新版本重新编辑",
+		"data": map[string]interface{}{
+			"current_version": current.Version,
+			"post":            current,
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+func TestRequestedPostVersion(t *testing.T) {
+	post := models.Post{ID: 7, Version: 3}
+	bodyVersion := 2
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		bodyVersion *int
+		want        int
+		wantOK      bool
+	}{
+		{"未提供版本", "", nil, 0, false},
+		{"使用请求体中的版本", "", &bodyVersion, 2, true},
+		{"匹配当前 ETag", `"post-7-v3"`, nil, 3, true},
+		{"弱 ETag", `W/"post-7-v3"`, nil, 3, true},
+		{"通配符接受当前版本", "*", nil, 3, true},
+		{"直接传入版本号", `"2"`, nil, 2, true},
+		{"不带引号的版本号", "5", nil, 5, true},
+		{"其他文章的 ETag 视为不匹配", `"post-8-v3"`, nil, 0, true},
+		{"请求头优先于请求体", `"post-7-v3"`, &bodyVersion, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := app.NewContext(0)
+			if tt.ifMatch != "" {
+				ctx.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			got, ok := requestedPostVersion(ctx, post, tt.bodyVersion)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("requestedPostVersion() = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/alvinhmg/blog/utils"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"gorm.io/gorm"
+)
+
+// descriptionLength 自动生成的描述的最大字符数
+const descriptionLength = 160
+
+// SEOMetaTag 页面 meta 标签，name 与 property 二选一
+type SEOMetaTag struct {
+	Name     string `json:"name,omitempty"`
+	Property string `json:"property,omitempty"`
+	Content  string `json:"content"`
+}
+
+// SEOAlternate 其他语言版本链接 (hreflang)
+type SEOAlternate struct {
+	Lang string `json:"hreflang"`
+	URL  string `json:"href"`
+}
+
+// SEOPage 计算后的页面元信息
+type SEOPage struct {
+	Title       string                 `json:"title"` // <title> 标签内容
+	Description string                 `json:"description"`
+	Canonical   string                 `json:"canonical"`
+	Robots      string                 `json:"robots"`
+	Meta        []SEOMetaTag           `json:"meta"`
+	Alternates  []SEOAlternate         `json:"alternates,omitempty"`
+	JSONLD      map[string]interface{} `json:"json_ld,omitempty"` // 结构化数据，目前仅文章页提供 BlogPosting
+}
+
+// siteName 站点名称
+func siteName() string {
+	return config.GetEnv("SITE_NAME", "Alvin Blog")
+}
+
+// siteURL 站点根地址，不含末尾的斜杠
+func siteURL() string {
+	return strings.TrimRight(config.GetEnv("SITE_URL", "http://localhost:5173"), "/")
+}
+
+// absoluteURL 将站内路径转换为绝对地址，已是绝对地址时原样返回
+func absoluteURL(path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return siteURL() + path
+}
+
+// validateSEOMeta 校验 SEO 元数据的长度、链接格式和 Twitter 卡片类型
+func validateSEOMeta(seo models.SEOMeta) error {
+	for _, text := range []string{seo.Title, seo.OGTitle, seo.TwitterTitle} {
+		if utf8.RuneCountInString(text) > 200 {
+			return errors.New("SEO 标题长度不能超过200")
+		}
+	}
+	for _, text := range []string{seo.Description, seo.OGDescription, seo.TwitterDescription} {
+		if utf8.RuneCountInString(text) > 500 {
+			return errors.New("SEO 描述长度不能超过500")
+		}
+	}
+	for _, link := range []string{seo.CanonicalURL, seo.OGImage, seo.TwitterImage} {
+		if link == "" || strings.HasPrefix(link, "/") {
+			continue
+		}
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(link) > 255 {
+			return errors.New("SEO 链接必须是站内路径或有效的 http(s) 地址: " + link)
+		}
+	}
+	switch seo.TwitterCard {
+	case "", "summary", "summary_large_image":
+	default:
+		return errors.New("无效的 Twitter 卡片类型: " + seo.TwitterCard)
+	}
+	return nil
+}
+
+// seoColumns 将 SEO 元数据转换为更新用的列映射
+func seoColumns(seo models.SEOMeta) map[string]interface{} {
+	return map[string]interface{}{
+		"seo_title":               seo.Title,
+		"seo_description":         seo.Description,
+		"seo_canonical_url":       seo.CanonicalURL,
+		"seo_no_index":            seo.NoIndex,
+		"seo_og_title":            seo.OGTitle,
+		"seo_og_description":      seo.OGDescription,
+		"seo_og_image":            seo.OGImage,
+		"seo_twitter_card":        seo.TwitterCard,
+		"seo_twitter_title":       seo.TwitterTitle,
+		"seo_twitter_description": seo.TwitterDescription,
+		"seo_twitter_image":       seo.TwitterImage,
+	}
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// ogLocale 将语言代码转换为 OpenGraph 区域格式
+func ogLocale(lang string) string {
+	switch lang {
+	case "zh":
+		return "zh_CN"
+	case "en":
+		return "en_US"
+	}
+	return strings.ReplaceAll(lang, "-", "_")
+}
+
+// buildSEOPage 按覆盖值优先、默认值兜底的规则生成通用的页面元信息
+func buildSEOPage(seo models.SEOMeta, name, description, path, image, ogType string) SEOPage {
+	title := seo.Title
+	if title == "" {
+		title = name + " - " + siteName()
+	}
+	description = firstNonEmpty(seo.Description, description)
+	canonical := absoluteURL(firstNonEmpty(seo.CanonicalURL, path))
+	robots := "index, follow"
+	if seo.NoIndex {
+		robots = "noindex, follow"
+	}
+
+	ogTitle := firstNonEmpty(seo.OGTitle, seo.Title, name)
+	ogDescription := firstNonEmpty(seo.OGDescription, description)
+	ogImage := absoluteURL(firstNonEmpty(seo.OGImage, image))
+	twitterImage := absoluteURL(firstNonEmpty(seo.TwitterImage, seo.OGImage, image))
+	twitterCard := seo.TwitterCard
+	if twitterCard == "" {
+		twitterCard = "summary"
+		if twitterImage != "" {
+			twitterCard = "summary_large_image"
+		}
+	}
+
+	meta := []SEOMetaTag{
+		{Name: "description", Content: description},
+		{Name: "robots", Content: robots},
+		{Property: "og:site_name", Content: siteName()},
+		{Property: "og:type", Content: ogType},
+		{Property: "og:title", Content: ogTitle},
+		{Property: "og:description", Content: ogDescription},
+		{Property: "og:url", Content: canonical},
+	}
+	if ogImage != "" {
+		meta = append(meta, SEOMetaTag{Property: "og:image", Content: ogImage})
+	}
+	meta = append(meta,
+		SEOMetaTag{Name: "twitter:card", Content: twitterCard},
+		SEOMetaTag{Name: "twitter:title", Content: firstNonEmpty(seo.TwitterTitle, ogTitle)},
+		SEOMetaTag{Name: "twitter:description", Content: firstNonEmpty(seo.TwitterDescription, ogDescription)},
+	)
+	if twitterImage != "" {
+		meta = append(meta, SEOMetaTag{Name: "twitter:image", Content: twitterImage})
+	}
+
+	return SEOPage{
+		Title:       title,
+		Description: description,
+		Canonical:   canonical,
+		Robots:      robots,
+		Meta:        meta,
+	}
+}
+
+// authorName 作者显示名称
+func authorName(user models.User) string {
+	return firstNonEmpty(user.Nickname, user.Username)
+}
+
+// postSEOPage 生成文章页元信息及 BlogPosting 结构化数据
+func postSEOPage(post models.Post) SEOPage {
+	path := "/posts/" + strconv.Itoa(int(post.ID))
+	description := firstNonEmpty(post.Excerpt, utils.Summarize(post.Content, descriptionLength))
+	page := buildSEOPage(post.SEO, post.Title, description, path, post.CoverImage, "article")
+	publishedAt := post.CreatedAt
+	if post.PublishedAt != nil {
+		publishedAt = *post.PublishedAt
+	}
+
+	page.Meta = append(page.Meta,
+		SEOMetaTag{Property: "og:locale", Content: ogLocale(post.Lang)},
+		SEOMetaTag{Property: "article:published_time", Content: publishedAt.Format(time.RFC3339)},
+		SEOMetaTag{Property: "article:modified_time", Content: post.UpdatedAt.Format(time.RFC3339)},
+		SEOMetaTag{Property: "article:author", Content: authorName(post.Author)},
+	)
+	if len(post.Categories) > 0 {
+		page.Meta = append(page.Meta, SEOMetaTag{Property: "article:section", Content: post.Categories[0].Name})
+	}
+	keywords := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		keywords = append(keywords, tag.Name)
+		page.Meta = append(page.Meta, SEOMetaTag{Property: "article:tag", Content: tag.Name})
+	}
+
+	// 其他语言版本，包含当前页面自身
+	if translations, err := loadTranslations(post); err == nil && len(translations) > 0 {
+		page.Alternates = append(page.Alternates, SEOAlternate{Lang: post.Lang, URL: page.Canonical})
+		for _, translation := range translations {
+			page.Alternates = append(page.Alternates, SEOAlternate{
+				Lang: translation.Lang,
+				URL:  absoluteURL("/posts/" + strconv.Itoa(int(translation.ID))),
+			})
+		}
+	}
+
+	// 主作者与作者角色的共同作者署名为 author，编辑和译者分别列出
+	person := func(user models.User) map[string]interface{} {
+		return map[string]interface{}{"@type": "Person", "name": authorName(user)}
+	}
+	authors := []map[string]interface{}{person(post.Author)}
+	var editors, translators []map[string]interface{}
+	for _, coauthor := range post.Coauthors {
+		switch coauthor.Role {
+		case models.PostAuthorRoleEditor:
+			editors = append(editors, person(coauthor.User))
+		case models.PostAuthorRoleTranslator:
+			translators = append(translators, person(coauthor.User))
+		default:
+			authors = append(authors, person(coauthor.User))
+		}
+	}
+
+	jsonLD := map[string]interface{}{
+		"@context":      "https://schema.org",
+		"@type":         "BlogPosting",
+		"headline":      post.Title,
+		"description":   page.Description,
+		"url":           page.Canonical,
+		"datePublished": publishedAt.Format(time.RFC3339),
+		"dateModified":  post.UpdatedAt.Format(time.RFC3339),
+		"inLanguage":    post.Lang,
+		"author":        authors,
+		"publisher": map[string]interface{}{
+			"@type": "Organization",
+			"name":  siteName(),
+			"url":   siteURL(),
+		},
+		"mainEntityOfPage": map[string]interface{}{
+			"@type": "WebPage",
+			"@id":   page.Canonical,
+		},
+	}
+	if image := absoluteURL(firstNonEmpty(post.SEO.OGImage, post.CoverImage)); image != "" {
+		jsonLD["image"] = image
+	}
+	if len(editors) > 0 {
+		jsonLD["editor"] = editors
+	}
+	if len(translators) > 0 {
+		jsonLD["translator"] = translators
+	}
+	if len(keywords) > 0 {
+		jsonLD["keywords"] = strings.Join(keywords, ",")
+	}
+	if len(post.Categories) > 0 {
+		sections := make([]string, 0, len(post.Categories))
+		for _, category := range post.Categories {
+			sections = append(sections, category.Name)
+		}
+		jsonLD["articleSection"] = sections
+	}
+	page.JSONLD = jsonLD
+
+	return page
+}
+
+// findByIDOrSlug 按ID或slug查询记录
+func findByIDOrSlug(db *gorm.DB, dest interface{}, key string) error {
+	if id, err := strconv.Atoi(key); err == nil {
+		return db.First(dest, id).Error
+	}
+	return db.Where("slug = ?", key).First(dest).Error
+}
+
+// GetSEOMeta 计算指定前台页面地址的 meta 标签和结构化数据
+func GetSEOMeta(c context.Context, ctx *app.RequestContext) {
+	rawURL := ctx.Query("url")
+	if rawURL == "" {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "缺少页面地址 'url'",
+		})
+		return
+	}
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		ctx.JSON(consts.StatusBadRequest, map[string]interface{}{
+			"code":    400,
+			"message": "无效的页面地址",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	segments := strings.Split(strings.Trim(pageURL.Path, "/"), "/")
+	notFound := func() {
+		ctx.JSON(consts.StatusNotFound, map[string]interface{}{
+			"code":    404,
+			"message": "页面不存在",
+		})
+	}
+
+	var page SEOPage
+	switch {
+	case len(segments) == 1 && segments[0] == "":
+		page = buildSEOPage(models.SEOMeta{Title: siteName()}, siteName(), config.GetEnv("SITE_DESCRIPTION", ""), "/", "", "website")
+	case len(segments) == 1 && segments[0] == "posts":
+		page = buildSEOPage(models.SEOMeta{}, "全部文章", "", "/posts", "", "website")
+	case len(segments) == 1 && segments[0] == "archive":
+		page = buildSEOPage(models.SEOMeta{}, "文章归档", "", "/archive", "", "website")
+	case len(segments) == 2 && segments[0] == "posts":
+		// slug 在同一语言内唯一，按地址中的 lang 参数区分，未指定时使用默认语言
+		lang := firstNonEmpty(pageURL.Query().Get("lang"), defaultLang())
+		db := config.DB.Preload("Author").Scopes(preloadCoauthors).Preload("Categories").Preload("Tags").
+			Where("status = ?", models.PostStatusPublished)
+		if _, err := strconv.Atoi(segments[1]); err != nil {
+			db = db.Where("lang = ?", lang)
+		}
+		var post models.Post
+		if err := findByIDOrSlug(db, &post, segments[1]); err != nil {
+			notFound()
+			return
+		}
+		page = postSEOPage(post)
+	case len(segments) == 2 && segments[0] == "categories":
+		var category models.Category
+		if err := findByIDOrSlug(config.DB, &category, segments[1]); err != nil {
+			notFound()
+			return
+		}
+		description := firstNonEmpty(category.Description, "分类「"+category.Name+"」下的全部文章")
+		page = buildSEOPage(category.SEO, category.Name, description, "/categories/"+strconv.Itoa(int(category.ID)), "", "website")
+	case len(segments) == 2 && segments[0] == "tags":
+		var tag models.Tag
+		if err := findByIDOrSlug(config.DB, &tag, segments[1]); err != nil {
+			notFound()
+			return
+		}
+		page = buildSEOPage(tag.SEO, tag.Name, "标签「"+tag.Name+"」下的全部文章", "/tags/"+strconv.Itoa(int(tag.ID)), "", "website")
+	default:
+		notFound()
+		return
+	}
+
+	ctx.JSON(consts.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取页面元信息成功",
+		"data":    page,
+	})
+}
@@ -0,0 +1,346 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/gosimple/slug"
+	"gorm.io/gorm"
+)
+
+// GetSeriesList 获取所有系列列表 (包含已发布文章数)
+func GetSeriesList(c context.Context, ctx *app.RequestContext) {
+	var seriesList []struct {
+		models.Series
+		PostCount int `json:"post_count"`
+	}
+
+	result := config.DB.Table("series").
+		Select("series.*, count(post.id) as post_count").
+		Joins("left join series_post on series_post.series_id = series.id").
+		Joins("left join post on post.id = series_post.post_id AND post.status = 'published' AND post.deleted_at IS NULL").
+		Where("series.deleted_at IS NULL").
+		Group("series.id").
+		Order("series.created_at DESC").
+		Scan(&seriesList)
+
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"code":    500,
+			"message": "获取系列列表失败",
+			"error":   result.Error.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取系列列表成功",
+		"data":    seriesList,
+	})
+}
+
+// GetSeries 获取系列详情 (按顺序包含已发布的文章)
+func GetSeries(c context.Context, ctx *app.RequestContext) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "无效的系列ID"})
+		return
+	}
+
+	var series models.Series
+	if err := config.DB.First(&series, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, map[string]interface{}{"code": 404, "message": "系列不存在"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "查询系列失败", "error": err.Error()})
+		}
+		return
+	}
+
+	var posts []models.Post
+	if err := config.DB.Model(&models.Post{}).
+		Select("post.id, post.title, post.slug, post.excerpt, post.cover_image, post.status, post.created_at, post.author_id").
+		Joins("JOIN series_post ON series_post.post_id = post.id").
+		Where("series_post.series_id = ? AND post.status = ?", series.ID, "published").
+		Order("series_post.position ASC").
+		Preload("Author").
+		Find(&posts).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "获取系列文章失败", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"code":    200,
+		"message": "获取系列详情成功",
+		"data": map[string]interface{}{
+			"series": series,
+			"posts":  posts,
+		},
+	})
+}
+
+// --- 以下是管理员权限的操作 ---
+
+// CreateSeries 创建系列
+func CreateSeries(c context.Context, ctx *app.RequestContext) {
+	var req struct {
+		Title       string `json:"title" vd:"len($)>0 && len($)<=200"`
+		Slug        string `json:"slug,omitempty" vd:"len($)<=200"`
+		Description string `json:"description,omitempty" vd:"len($)<=500"`
+		CoverImage  string `json:"cover_image,omitempty" vd:"len($)<=255"`
+		PostIDs     []uint `json:"post_ids,omitempty"` // 按顺序排列的文章ID
+	}
+
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
+		return
+	}
+
+	series := models.Series{
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Description: req.Description,
+		CoverImage:  req.CoverImage,
+	}
+	if series.Slug == "" {
+		series.Slug = slug.Make(req.Title)
+	}
+
+	if err := checkSeriesPostIDs(req.PostIDs); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": err.Error()})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+		return replaceSeriesPosts(tx, series.ID, req.PostIDs)
+	})
+	if err != nil {
+		// 考虑处理唯一约束冲突等错误
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "创建系列失败", "error": err.Error()})
+		return
+	}
+
+	config.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&series, series.ID)
+
+	ctx.JSON(http.StatusCreated, map[string]interface{}{"code": 201, "message": "创建系列成功", "data": series})
+}
+
+// UpdateSeries 更新系列 (post_ids 不为空时按给定顺序替换系列文章)
+func UpdateSeries(c context.Context, ctx *app.RequestContext) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "无效的系列ID"})
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title,omitempty" vd:"len($)<=200"`
+		Slug        string `json:"slug,omitempty" vd:"len($)<=200"`
+		Description string `json:"description,omitempty" vd:"len($)<=500"`
+		CoverImage  string `json:"cover_image,omitempty" vd:"len($)<=255"`
+		PostIDs     []uint `json:"post_ids"`
+	}
+
+	if err := ctx.BindAndValidate(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
+		return
+	}
+
+	var series models.Series
+	if err := config.DB.First(&series, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, map[string]interface{}{"code": 404, "message": "系列不存在"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "查询系列失败", "error": err.Error()})
+		}
+		return
+	}
+
+	if err := checkSeriesPostIDs(req.PostIDs); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": err.Error()})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != "" {
+		updates["title"] = req.Title
+	}
+	if req.Slug != "" {
+		updates["slug"] = req.Slug
+	}
+	if req.Description != "" {
+		updates["description"] = req.Description
+	}
+	if req.CoverImage != "" {
+		updates["cover_image"] = req.CoverImage
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&series).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if req.PostIDs != nil {
+			return replaceSeriesPosts(tx, series.ID, req.PostIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "更新系列失败", "error": err.Error()})
+		return
+	}
+
+	config.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&series, series.ID)
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"code": 200, "message": "更新系列成功", "data": series})
+}
+
+// DeleteSeries 删除系列 (文章本身保留，仅解除关联)
+func DeleteSeries(c context.Context, ctx *app.RequestContext) {
+	idStr := ctx.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "无效的系列ID"})
+		return
+	}
+
+	var series models.Series
+	if err := config.DB.First(&series, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, map[string]interface{}{"code": 404, "message": "系列不存在"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "查询系列失败", "error": err.Error()})
+		}
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// 解除文章与该系列的关联
+		if err := tx.Where("series_id = ?", series.ID).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		// 删除系列本身 (软删除)
+		return tx.Delete(&models.Series{}, id).Error
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{"code": 500, "message": "删除系列失败", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{"code": 200, "message": "删除系列成功"})
+}
+
+// checkSeriesPostIDs 检查系列文章ID不重复且都存在
+func checkSeriesPostIDs(postIDs []uint) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	seen := make(map[uint]bool, len(postIDs))
+	for _, postID := range postIDs {
+		if seen[postID] {
+			return errors.New("系列中包含重复的文章")
+		}
+		seen[postID] = true
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.Post{}).Where("id IN ?", postIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(postIDs) {
+		return errors.New("部分文章不存在")
+	}
+	return nil
+}
+
+// replaceSeriesPosts 按给定顺序替换系列中的文章，已属于其他系列的文章会被移入该系列
+func replaceSeriesPosts(tx *gorm.DB, seriesID uint, postIDs []uint) error {
+	if err := tx.Where("series_id = ?", seriesID).Delete(&models.SeriesPost{}).Error; err != nil {
+		return err
+	}
+	if len(postIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("post_id IN ?", postIDs).Delete(&models.SeriesPost{}).Error; err != nil {
+		return err
+	}
+
+	items := make([]models.SeriesPost, 0, len(postIDs))
+	for i, postID := range postIDs {
+		items = append(items, models.SeriesPost{
+			SeriesID: seriesID,
+			PostID:   postID,
+			Position: i + 1,
+		})
+	}
+	return tx.Omit("Post").Create(&items).Error
+}
+
+// loadSeriesNav 加载文章所属系列的导航信息，文章不属于任何系列时返回nil
+func loadSeriesNav(post *models.Post) (*models.SeriesNav, error) {
+	var item models.SeriesPost
+	result := config.DB.Where("post_id = ?", post.ID).Limit(1).Find(&item)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+
+	var series models.Series
+	if err := config.DB.First(&series, item.SeriesID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// 导航只包含已发布的文章，以及当前文章本身 (便于预览草稿)
+	var members []models.PostSummary
+	if err := config.DB.Model(&models.Post{}).
+		Select("post.id, post.title, post.slug").
+		Joins("JOIN series_post ON series_post.post_id = post.id").
+		Where("series_post.series_id = ? AND (post.status = ? OR post.id = ?)", series.ID, "published", post.ID).
+		Order("series_post.position ASC").
+		Scan(&members).Error; err != nil {
+		return nil, err
+	}
+
+	nav := &models.SeriesNav{
+		ID:    series.ID,
+		Title: series.Title,
+		Slug:  series.Slug,
+		Total: len(members),
+	}
+	for i, member := range members {
+		if member.ID != post.ID {
+			continue
+		}
+		nav.Part = i + 1
+		if i > 0 {
+			prev := members[i-1]
+			nav.Prev = &prev
+		}
+		if i+1 < len(members) {
+			next := members[i+1]
+			nav.Next = &next
+		}
+		break
+	}
+	return nav, nil
+}
@@ -88,18 +88,24 @@ func GetHotTags(c context.Context, ctx *app.RequestContext) {
 // CreateTag 创建标签
 func CreateTag(c context.Context, ctx *app.RequestContext) {
 	var req struct {
-		Name string `json:"name" vd:"len($)>0 && len($)<=50"`
-		Slug string `json:"slug" vd:"len($)>0 && len($)<=50"`
+		Name string         `json:"name" vd:"len($)>0 && len($)<=50"`
+		Slug string         `json:"slug" vd:"len($)>0 && len($)<=50"`
+		SEO  models.SEOMeta `json:"seo"`
 	}
 
 	if err := ctx.BindAndValidate(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
 		return
 	}
+	if err := validateSEOMeta(req.SEO); err != nil {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": err.Error()})
+		return
+	}
 
 	tag := models.Tag{
 		Name: req.Name,
 		Slug: req.Slug,
+		SEO:  req.SEO,
 	}
 
 	if err := config.DB.Create(&tag).Error; err != nil {
@@ -120,14 +126,21 @@ func UpdateTag(c context.Context, ctx *app.RequestContext) {
 	}
 
 	var req struct {
-		Name string `json:"name,omitempty" vd:"len($)<=50"`
-		Slug string `json:"slug,omitempty" vd:"len($)<=50"`
+		Name string          `json:"name,omitempty" vd:"len($)<=50"`
+		Slug string          `json:"slug,omitempty" vd:"len($)<=50"`
+		SEO  *models.SEOMeta `json:"seo"` // 不为nil时整体替换 SEO 元数据
 	}
 
 	if err := ctx.BindAndValidate(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": "参数错误", "error": err.Error()})
 		return
 	}
+	if req.SEO != nil {
+		if err := validateSEOMeta(*req.SEO); err != nil {
+			ctx.JSON(http.StatusBadRequest, map[string]interface{}{"code": 400, "message": err.Error()})
+			return
+		}
+	}
 
 	var tag models.Tag
 	if err := config.DB.First(&tag, id).Error; err != nil {
@@ -146,6 +159,11 @@ func UpdateTag(c context.Context, ctx *app.RequestContext) {
 	if req.Slug != "" {
 		updates["slug"] = req.Slug
 	}
+	if req.SEO != nil {
+		for column, value := range seoColumns(*req.SEO) {
+			updates[column] = value
+		}
+	}
 
 	if len(updates) > 0 {
 		if err := config.DB.Model(&tag).Updates(updates).Error; err != nil {
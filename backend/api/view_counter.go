@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alvinhmg/blog/config"
+	"github.com/alvinhmg/blog/models"
+	"github.com/cloudwego/hertz/pkg/app"
+	"gorm.io/gorm"
+)
+
+// 常见爬虫和脚本客户端的 User-Agent 关键字
+var botUserAgentKeywords = []string{
+	"bot", "spider", "crawl", "slurp", "curl", "wget", "python-requests",
+	"go-http-client", "headless", "httpclient", "okhttp", "feedfetcher", "preview",
+}
+
+// viewCounter 浏览量计数器：按访客去重、在内存中缓冲增量并定期批量写入数据库
+type viewCounter struct {
+	mu       sync.Mutex
+	window   time.Duration        // 同一访客重复浏览的去重窗口
+	seen     map[string]time.Time // 访客指纹+文章ID -> 最近一次计数时间
+	pending  map[uint]int         // 文章ID -> 尚未写入数据库的浏览增量
+	maxBatch int                  // 缓冲的文章数达到该值时立即写入
+	flushing chan struct{}
+}
+
+var views = &viewCounter{
+	seen:     make(map[string]time.Time),
+	pending:  make(map[uint]int),
+	flushing: make(chan struct{}, 1),
+}
+
+// StartViewCounter 启动浏览量定时写入任务，ctx 结束时写入剩余的缓冲数据
+func StartViewCounter(ctx context.Context) {
+	views.mu.Lock()
+	views.window = config.GetEnvDuration("VIEW_DEDUP_WINDOW", 30*time.Minute)
+	views.maxBatch = config.GetEnvInt("VIEW_FLUSH_BATCH", 100)
+	views.mu.Unlock()
+	interval := config.GetEnvDuration("VIEW_FLUSH_INTERVAL", 30*time.Second)
+	if interval <= 0 {
+		// time.NewTicker 不接受非正数的间隔，配置无效时使用默认值
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				FlushViewCounts()
+			case <-views.flushing:
+				FlushViewCounts()
+			case <-ctx.Done():
+				FlushViewCounts()
+				return
+			}
+		}
+	}()
+}
+
+// FlushViewCounts 将缓冲的浏览增量批量写入数据库
+func FlushViewCounts() {
+	views.mu.Lock()
+	pending := views.pending
+	views.pending = make(map[uint]int)
+	// 顺带清理过期的去重记录
+	now := time.Now()
+	for key, t := range views.seen {
+		if now.Sub(t) >= views.window {
+			delete(views.seen, key)
+		}
+	}
+	views.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for postID, count := range pending {
+			if err := tx.Model(&models.Post{}).Where("id = ?", postID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", count)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// 写入失败时放回缓冲区，等待下次重试
+		log.Println("写入浏览量失败:", err)
+		views.mu.Lock()
+		for postID, count := range pending {
+			views.pending[postID] += count
+		}
+		views.mu.Unlock()
+	}
+}
+
+// recordView 记录一次文章浏览，返回该文章尚未写入数据库的浏览增量
+func recordView(ctx *app.RequestContext, postID uint) int {
+	userAgent := string(ctx.UserAgent())
+	if isBotUserAgent(userAgent) {
+		return pendingViews(postID)
+	}
+
+	key := visitorFingerprint(ctx, userAgent) + ":" + strconv.FormatUint(uint64(postID), 10)
+	now := time.Now()
+
+	views.mu.Lock()
+	if last, ok := views.seen[key]; ok && now.Sub(last) < views.window {
+		count := views.pending[postID]
+		views.mu.Unlock()
+		return count
+	}
+	views.seen[key] = now
+	views.pending[postID]++
+	count := views.pending[postID]
+	full := views.maxBatch > 0 && len(views.pending) >= views.maxBatch
+	views.mu.Unlock()
+
+	if full {
+		// 通知后台任务提前写入，不阻塞当前请求
+		select {
+		case views.flushing <- struct{}{}:
+		default:
+		}
+	}
+	return count
+}
+
+// pendingViews 获取文章尚未写入数据库的浏览增量
+func pendingViews(postID uint) int {
+	views.mu.Lock()
+	defer views.mu.Unlock()
+	return views.pending[postID]
+}
+
+// visitorFingerprint 生成访客指纹：登录用户使用用户ID，匿名访客使用IP和User-Agent的摘要
+func visitorFingerprint(ctx *app.RequestContext, userAgent string) string {
+	if userID, exists := ctx.Get("userID"); exists {
+		return "u" + strconv.FormatUint(uint64(userID.(uint)), 10)
+	}
+	sum := sha256.Sum256([]byte(ctx.ClientIP() + "|" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+// isBotUserAgent 判断是否为已知的爬虫或脚本客户端
+func isBotUserAgent(userAgent string) bool {
+	if userAgent == "" {
+		return true
+	}
+	ua := strings.ToLower(userAgent)
+	for _, keyword := range botUserAgentKeywords {
+		if strings.Contains(ua, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -68,7 +68,30 @@ func migrateModels() {
 		&models.Tag{},
 		&models.Post{},
 		&models.Comment{},
+		&models.CommentRevision{},
+		&models.CommentReaction{},
+		&models.CommentReport{},
+		&models.PostAuthor{},
+		&models.PostLike{},
+		&models.PostEditLock{},
+		&models.PostAutosave{},
+		&models.PostReviewNote{},
+		&models.PostMetaField{},
+		&models.PostMeta{},
+		&models.Series{},
+		&models.SeriesPost{},
 	)
+
+	// 文章slug改为按语言唯一，移除旧的全局唯一索引
+	for _, name := range []string{"slug", "idx_post_slug"} {
+		if DB.Migrator().HasIndex(&models.Post{}, name) {
+			DB.Migrator().DropIndex(&models.Post{}, name)
+		}
+	}
+
+	// 已发布的旧文章以创建时间作为发布时间
+	DB.Model(&models.Post{}).Where("status = ? AND published_at IS NULL", models.PostStatusPublished).
+		UpdateColumn("published_at", gorm.Expr("created_at"))
 }
 
 // 获取环境变量，如果不存在则返回默认值
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// GetEnv 获取环境变量，如果不存在则返回默认值
+func GetEnv(key, defaultValue string) string {
+	return getEnv(key, defaultValue)
+}
+
+// GetEnvInt 获取整数类型的环境变量，不存在或格式错误时返回默认值
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvDuration 获取时长类型的环境变量 (如 "30m"、"10s")，不存在或格式错误时返回默认值
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
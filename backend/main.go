@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 
+	"github.com/alvinhmg/blog/api"
 	"github.com/alvinhmg/blog/config"
 	"github.com/alvinhmg/blog/routes"
 	"github.com/cloudwego/hertz/pkg/app"
@@ -16,7 +17,8 @@ func corsMiddleware() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
 		c.Response.Header.Set("Access-Control-Allow-Origin", "*")
 		c.Response.Header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Response.Header.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Response.Header.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		c.Response.Header.Set("Access-Control-Expose-Headers", "ETag")
 		c.Response.Header.Set("Access-Control-Max-Age", "86400")
 
 		// 处理 OPTIONS 预检请求
@@ -39,12 +41,25 @@ func main() {
 	// 初始化数据库连接
 	config.InitDB()
 
+	// 启动后台任务：浏览量批量写入、回收站定时清理
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	api.StartViewCounter(bgCtx)
+	api.StartTrashPurger(bgCtx)
+
 	// 创建Hertz服务器实例
 	h := server.Default(
 		server.WithHostPorts(":8080"),
 	)
+	// 只信任配置的反向代理转发的客户端IP
+	h.SetClientIPFunc(api.NewClientIPFunc())
 	h.Use(corsMiddleware())
 
+	// 关闭服务时停止后台任务，并写入缓冲中的浏览量
+	h.OnShutdown = append(h.OnShutdown, func(ctx context.Context) {
+		stopBackground()
+		api.FlushViewCounts()
+	})
+
 	// 注册路由
 	routes.RegisterRoutes(h)
 
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 // JWTAuth JWT认证中间件
@@ -32,20 +34,19 @@ func JWTAuth() app.HandlerFunc {
 		// 移除Bearer前缀
 		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
-		// 解析JWT令牌
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// 验证签名算法
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("无效的签名方法: %v", token.Header["alg"])
-			}
-			// 返回密钥
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-
+		// 解析JWT令牌并查询用户信息
+		user, err := parseUserFromToken(tokenString)
 		if err != nil {
+			message := "无效的认证令牌"
+			switch {
+			case errors.Is(err, errInvalidUserID):
+				message = "无效的用户ID"
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				message = "用户不存在"
+			}
 			ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
 				"code":    401,
-				"message": "无效的认证令牌",
+				"message": message,
 				"error":   err.Error(),
 				"data":    nil,
 			})
@@ -53,49 +54,55 @@ func JWTAuth() app.HandlerFunc {
 			return
 		}
 
-		// 验证令牌有效性
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok || !token.Valid {
-			ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
-				"code":    401,
-				"message": "无效的认证令牌",
-				"data":    nil,
-			})
-			ctx.Abort()
-			return
-		}
+		// 将用户信息存储到上下文中
+		ctx.Set("user", user)
+		ctx.Set("userID", user.ID)
+		ctx.Next(c)
+	}
+}
 
-		// 获取用户ID
-		userIDFloat, ok := claims["user_id"].(float64)
-		if !ok {
-			ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
-				"code":    401,
-				"message": "无效的用户ID",
-				"data":    nil,
-			})
-			ctx.Abort()
-			return
+// OptionalJWTAuth 可选JWT认证中间件，携带有效令牌时设置用户信息，否则按匿名访问继续处理
+func OptionalJWTAuth() app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		tokenString := strings.TrimPrefix(string(ctx.GetHeader("Authorization")), "Bearer ")
+		if tokenString != "" {
+			if user, err := parseUserFromToken(tokenString); err == nil {
+				ctx.Set("user", user)
+				ctx.Set("userID", user.ID)
+			}
 		}
-		userID := uint(userIDFloat)
+		ctx.Next(c)
+	}
+}
 
-		// 查询用户信息
-		var user models.User
-		result := config.DB.First(&user, userID)
-		if result.Error != nil {
-			ctx.JSON(consts.StatusUnauthorized, map[string]interface{}{
-				"code":    401,
-				"message": "用户不存在",
-				"data":    nil,
-			})
-			ctx.Abort()
-			return
+// errInvalidUserID 令牌中缺少有效的用户ID
+var errInvalidUserID = errors.New("无效的用户ID")
+
+// parseUserFromToken 解析JWT令牌并查询对应用户，JWTAuth 和 OptionalJWTAuth 共用
+func parseUserFromToken(tokenString string) (models.User, error) {
+	var user models.User
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("无效的签名方法: %v", token.Header["alg"])
 		}
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil {
+		return user, err
+	}
 
-		// 将用户信息存储到上下文中
-		ctx.Set("user", user)
-		ctx.Set("userID", userID)
-		ctx.Next(c)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return user, errors.New("无效的认证令牌")
 	}
+	userIDFloat, ok := claims["user_id"].(float64)
+	if !ok {
+		return user, errInvalidUserID
+	}
+
+	err = config.DB.First(&user, uint(userIDFloat)).Error
+	return user, err
 }
 
 // GenerateToken 生成JWT令牌
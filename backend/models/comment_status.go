@@ -0,0 +1,138 @@
+package models
+
+// 评论状态
+const (
+	CommentStatusPending  = "pending"  // 待审核
+	CommentStatusApproved = "approved" // 已通过
+	CommentStatusRejected = "rejected" // 已拒绝
+	CommentStatusSpam     = "spam"     // 垃圾评论
+)
+
+// 文章评论策略
+const (
+	CommentPolicyInherit    = "inherit"    // 跟随站点设置
+	CommentPolicyOpen       = "open"       // 开放评论
+	CommentPolicyClosed     = "closed"     // 关闭评论
+	CommentPolicyRegistered = "registered" // 仅登录用户可评论
+)
+
+// 评论审核方式
+const (
+	CommentModerationInherit  = "inherit"  // 跟随站点设置
+	CommentModerationRequired = "required" // 评论需人工审核
+	CommentModerationAuto     = "auto"     // 评论自动通过
+)
+
+// 用户评论信任级别
+const (
+	CommentTrustAuto      = "auto"      // 按历史评论记录自动判断
+	CommentTrustTrusted   = "trusted"   // 管理员标记为可信，评论自动通过
+	CommentTrustModerated = "moderated" // 管理员标记为始终需要审核
+)
+
+// 评论反应类型：upvote 计入评论得分，其余为固定的表情反应
+const (
+	CommentReactionUpvote   = "upvote"   // 赞同
+	CommentReactionHeart    = "heart"    // ❤️
+	CommentReactionLaugh    = "laugh"    // 😄
+	CommentReactionHooray   = "hooray"   // 🎉
+	CommentReactionConfused = "confused" // 😕
+	CommentReactionEyes     = "eyes"     // 👀
+)
+
+// CommentReactionTypes 支持的评论反应类型
+var CommentReactionTypes = []string{
+	CommentReactionUpvote, CommentReactionHeart, CommentReactionLaugh,
+	CommentReactionHooray, CommentReactionConfused, CommentReactionEyes,
+}
+
+// 评论举报原因
+const (
+	CommentReportReasonSpam       = "spam"       // 垃圾广告
+	CommentReportReasonAbuse      = "abuse"      // 辱骂或仇恨言论
+	CommentReportReasonHarassment = "harassment" // 骚扰他人
+	CommentReportReasonOffTopic   = "off_topic"  // 与文章无关
+	CommentReportReasonOther      = "other"      // 其他，需填写说明
+)
+
+// 评论举报处理状态
+const (
+	CommentReportStatusOpen      = "open"      // 待处理
+	CommentReportStatusResolved  = "resolved"  // 已处理，评论被拒绝或标记为垃圾
+	CommentReportStatusDismissed = "dismissed" // 已驳回，评论保持公开
+)
+
+// commentStatusTransitions 评论审核状态机：允许的状态流转
+var commentStatusTransitions = map[string][]string{
+	CommentStatusPending:  {CommentStatusApproved, CommentStatusRejected, CommentStatusSpam},
+	CommentStatusApproved: {CommentStatusRejected, CommentStatusSpam},
+	CommentStatusRejected: {CommentStatusApproved, CommentStatusSpam, CommentStatusPending},
+	CommentStatusSpam:     {CommentStatusApproved, CommentStatusRejected, CommentStatusPending},
+}
+
+// IsValidCommentStatus 判断是否为有效的评论状态
+func IsValidCommentStatus(status string) bool {
+	_, ok := commentStatusTransitions[status]
+	return ok
+}
+
+// IsValidCommentPolicy 判断是否为有效的文章评论策略
+func IsValidCommentPolicy(policy string) bool {
+	switch policy {
+	case CommentPolicyInherit, CommentPolicyOpen, CommentPolicyClosed, CommentPolicyRegistered:
+		return true
+	}
+	return false
+}
+
+// IsValidCommentModeration 判断是否为有效的评论审核方式
+func IsValidCommentModeration(moderation string) bool {
+	switch moderation {
+	case CommentModerationInherit, CommentModerationRequired, CommentModerationAuto:
+		return true
+	}
+	return false
+}
+
+// IsValidCommentTrust 判断是否为有效的用户评论信任级别
+func IsValidCommentTrust(trust string) bool {
+	return trust == CommentTrustAuto || trust == CommentTrustTrusted || trust == CommentTrustModerated
+}
+
+// IsValidCommentReaction 判断是否为支持的评论反应类型
+func IsValidCommentReaction(reaction string) bool {
+	for _, t := range CommentReactionTypes {
+		if t == reaction {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidCommentReportReason 判断是否为有效的评论举报原因
+func IsValidCommentReportReason(reason string) bool {
+	switch reason {
+	case CommentReportReasonSpam, CommentReportReasonAbuse, CommentReportReasonHarassment,
+		CommentReportReasonOffTopic, CommentReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// IsValidCommentReportStatus 判断是否为有效的评论举报处理状态
+func IsValidCommentReportStatus(status string) bool {
+	return status == CommentReportStatusOpen || status == CommentReportStatusResolved || status == CommentReportStatusDismissed
+}
+
+// CanTransitionCommentStatus 判断评论状态能否从 from 流转到 to
+func CanTransitionCommentStatus(from, to string) bool {
+	if from == "" {
+		from = CommentStatusPending
+	}
+	for _, next := range commentStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
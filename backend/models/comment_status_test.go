@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestCanTransitionCommentStatus(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"", CommentStatusApproved, true},
+		{CommentStatusPending, CommentStatusApproved, true},
+		{CommentStatusPending, CommentStatusRejected, true},
+		{CommentStatusPending, CommentStatusSpam, true},
+		{CommentStatusPending, CommentStatusPending, false},
+		{CommentStatusApproved, CommentStatusRejected, true},
+		{CommentStatusApproved, CommentStatusSpam, true},
+		{CommentStatusApproved, CommentStatusPending, false},
+		{CommentStatusApproved, CommentStatusApproved, false},
+		{CommentStatusRejected, CommentStatusApproved, true},
+		{CommentStatusRejected, CommentStatusPending, true},
+		{CommentStatusSpam, CommentStatusApproved, true},
+		{CommentStatusSpam, CommentStatusRejected, true},
+		{"unknown", CommentStatusApproved, false},
+		{CommentStatusPending, "unknown", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionCommentStatus(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionCommentStatus(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
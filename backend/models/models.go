@@ -8,18 +8,34 @@ import (
 
 // User 用户模型
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	Username  string         `gorm:"size:50;not null;unique" json:"username"`
-	Email     string         `gorm:"size:100;not null;unique" json:"email"`
-	Password  string         `gorm:"size:100;not null" json:"-"`
-	Nickname  string         `gorm:"size:50" json:"nickname"`
-	Avatar    string         `gorm:"size:255" json:"avatar"`
-	Role      string         `gorm:"size:20;default:'user'" json:"role"` // admin 或 user
-	Posts     []Post         `gorm:"foreignKey:AuthorID" json:"-"`
-	Comments  []Comment      `json:"-"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	Username     string         `gorm:"size:50;not null;unique" json:"username"`
+	Email        string         `gorm:"size:100;not null;unique" json:"email"`
+	Password     string         `gorm:"size:100;not null" json:"-"`
+	Nickname     string         `gorm:"size:50" json:"nickname"`
+	Avatar       string         `gorm:"size:255" json:"avatar"`
+	Role         string         `gorm:"size:20;default:'user'" json:"role"`          // admin 或 user
+	CommentTrust string         `gorm:"size:20;default:'auto'" json:"comment_trust"` // auto, trusted, moderated
+	Posts        []Post         `gorm:"foreignKey:AuthorID" json:"-"`
+	Comments     []Comment      `json:"-"`
+}
+
+// SEOMeta 搜索引擎与社交分享元数据，留空的字段在生成页面元信息时使用默认值
+type SEOMeta struct {
+	Title              string `gorm:"size:200" json:"title"`
+	Description        string `gorm:"size:500" json:"description"`
+	CanonicalURL       string `gorm:"size:255" json:"canonical_url"`
+	NoIndex            bool   `gorm:"default:false" json:"noindex"` // 禁止搜索引擎收录
+	OGTitle            string `gorm:"size:200" json:"og_title"`
+	OGDescription      string `gorm:"size:500" json:"og_description"`
+	OGImage            string `gorm:"size:255" json:"og_image"`
+	TwitterCard        string `gorm:"size:30" json:"twitter_card"` // summary 或 summary_large_image
+	TwitterTitle       string `gorm:"size:200" json:"twitter_title"`
+	TwitterDescription string `gorm:"size:500" json:"twitter_description"`
+	TwitterImage       string `gorm:"size:255" json:"twitter_image"`
 }
 
 // Category 文章分类
@@ -31,6 +47,7 @@ type Category struct {
 	Name        string         `gorm:"size:50;not null;unique" json:"name"`
 	Slug        string         `gorm:"size:50;not null;unique" json:"slug"`
 	Description string         `gorm:"size:255" json:"description"`
+	SEO         SEOMeta        `gorm:"embedded;embeddedPrefix:seo_" json:"seo"`
 	Posts       []Post         `gorm:"many2many:post_categories" json:"-"`
 }
 
@@ -42,43 +59,276 @@ type Tag struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 	Name      string         `gorm:"size:50;not null;unique" json:"name"`
 	Slug      string         `gorm:"size:50;not null;unique" json:"slug"`
+	SEO       SEOMeta        `gorm:"embedded;embeddedPrefix:seo_" json:"seo"`
 	Posts     []Post         `gorm:"many2many:post_tags" json:"-"`
 }
 
 // Post 博客文章
 type Post struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
-	Title      string         `gorm:"size:200;not null" json:"title"`
-	Slug       string         `gorm:"size:200;not null;unique" json:"slug"`
-	Content    string         `gorm:"type:text;not null" json:"content"`
-	Excerpt    string         `gorm:"size:500" json:"excerpt"`
-	CoverImage string         `gorm:"size:255" json:"cover_image"`
-	Status     string         `gorm:"size:20;default:'draft'" json:"status"` // draft, published
-	ViewCount  int            `gorm:"default:0" json:"view_count"`
-	LikeCount  int            `gorm:"default:0" json:"like_count"`
-	AuthorID   uint           `json:"author_id"`
-	Author     User           `gorm:"foreignKey:AuthorID" json:"author"`
-	Categories []Category     `gorm:"many2many:post_categories" json:"categories"`
-	Tags       []Tag          `gorm:"many2many:post_tags" json:"tags"`
-	Comments   []Comment      `json:"comments"`
+	ID                   uint                   `gorm:"primaryKey" json:"id"`
+	CreatedAt            time.Time              `json:"created_at"`
+	UpdatedAt            time.Time              `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt         `gorm:"index" json:"-"`
+	Title                string                 `gorm:"size:200;not null" json:"title"`
+	Slug                 string                 `gorm:"size:200;not null;uniqueIndex:idx_post_slug_lang" json:"slug"`                   // 同一语言内唯一
+	Lang                 string                 `gorm:"size:10;not null;default:'zh';uniqueIndex:idx_post_slug_lang;index" json:"lang"` // 语言代码，如 zh、en
+	Content              string                 `gorm:"type:text;not null" json:"content"`
+	Excerpt              string                 `gorm:"size:500" json:"excerpt"`
+	CoverImage           string                 `gorm:"size:255" json:"cover_image"`
+	SEO                  SEOMeta                `gorm:"embedded;embeddedPrefix:seo_" json:"seo"`
+	Status               string                 `gorm:"size:20;default:'draft'" json:"status"`               // draft, pending_review, changes_requested, published
+	CommentPolicy        string                 `gorm:"size:20;default:'inherit'" json:"comment_policy"`     // inherit, open, closed, registered
+	CommentAutoCloseDays *int                   `json:"comment_auto_close_days"`                             // 发布后多少天自动关闭评论，为空时跟随站点设置，0 表示不自动关闭
+	CommentModeration    string                 `gorm:"size:20;default:'inherit'" json:"comment_moderation"` // inherit, required, auto
+	CommentSettings      *CommentSettings       `gorm:"-" json:"comment_settings,omitempty"`                 // 合并站点设置后的实际评论设置，不入库
+	PublishedAt          *time.Time             `json:"published_at"`                                        // 首次发布时间
+	ViewCount            int                    `gorm:"default:0" json:"view_count"`
+	LikeCount            int                    `gorm:"default:0" json:"like_count"`
+	IsPinned             bool                   `gorm:"default:false;index" json:"is_pinned"`   // 是否置顶
+	PinOrder             int                    `gorm:"default:0" json:"pin_order"`             // 置顶顺序，数值越小越靠前
+	IsFeatured           bool                   `gorm:"default:false;index" json:"is_featured"` // 是否精选
+	FeaturedUntil        *time.Time             `json:"featured_until"`                         // 精选到期时间，为空表示长期精选
+	Version              int                    `gorm:"not null;default:1" json:"version"`      // 版本号，每次编辑递增，用于并发编辑冲突检测
+	AuthorID             uint                   `json:"author_id"`
+	Author               User                   `gorm:"foreignKey:AuthorID" json:"author"`
+	Coauthors            []PostAuthor           `gorm:"foreignKey:PostID" json:"coauthors"` // 共同作者 (不含主作者)，按顺序排列
+	TranslationGroupID   *uint                  `gorm:"index" json:"translation_group_id"`  // 翻译分组，互为翻译的文章取相同的值 (分组中原文的ID)
+	ReviewerID           *uint                  `json:"reviewer_id"`                        // 指定的审核人
+	Reviewer             *User                  `gorm:"foreignKey:ReviewerID" json:"reviewer,omitempty"`
+	SubmittedAt          *time.Time             `json:"submitted_at"` // 最近一次提交审核的时间
+	Categories           []Category             `gorm:"many2many:post_categories" json:"categories"`
+	Tags                 []Tag                  `gorm:"many2many:post_tags" json:"tags"`
+	Comments             []Comment              `json:"comments"`
+	Series               *SeriesNav             `gorm:"-" json:"series,omitempty"`       // 所属系列导航信息，不入库
+	LikedByMe            *bool                  `gorm:"-" json:"liked_by_me,omitempty"`  // 当前登录用户是否已点赞，不入库
+	EditLock             *PostEditLock          `gorm:"-" json:"edit_lock,omitempty"`    // 当前有效的编辑锁，仅管理接口返回，不入库
+	Translations         []PostTranslation      `gorm:"-" json:"translations,omitempty"` // 其他语言版本，不入库
+	Meta                 map[string]interface{} `gorm:"-" json:"meta,omitempty"`         // 自定义字段值 (按字段类型转换)，不入库
+}
+
+// 共同作者角色
+const (
+	PostAuthorRoleAuthor     = "author"     // 作者
+	PostAuthorRoleEditor     = "editor"     // 编辑
+	PostAuthorRoleTranslator = "translator" // 译者
+)
+
+// PostAuthor 文章的共同作者，主作者仍记录在 Post.AuthorID
+type PostAuthor struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	PostID   uint   `gorm:"not null;uniqueIndex:idx_post_author_post_user" json:"post_id"`
+	UserID   uint   `gorm:"not null;uniqueIndex:idx_post_author_post_user;index" json:"user_id"`
+	User     User   `json:"user"`
+	Role     string `gorm:"size:20;default:'author'" json:"role"` // author, editor, translator
+	Position int    `gorm:"not null;default:0" json:"position"`   // 显示顺序，从1开始
+}
+
+// 自定义字段类型
+const (
+	MetaFieldTypeString  = "string"
+	MetaFieldTypeText    = "text"
+	MetaFieldTypeNumber  = "number"
+	MetaFieldTypeBoolean = "boolean"
+	MetaFieldTypeURL     = "url"
+	MetaFieldTypeDate    = "date"   // 格式 2006-01-02
+	MetaFieldTypeSelect  = "select" // 取值必须在 Options 中
+)
+
+// PostMetaField 文章自定义字段定义，由管理员维护
+type PostMetaField struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Key         string    `gorm:"column:meta_key;size:50;not null;unique" json:"key"`
+	Label       string    `gorm:"size:100" json:"label"`
+	Type        string    `gorm:"size:20;not null;default:'string'" json:"type"`
+	Options     string    `gorm:"type:text" json:"-"` // select 类型的可选值，JSON 数组
+	OptionList  []string  `gorm:"-" json:"options,omitempty"`
+	Required    bool      `gorm:"default:false" json:"required"`
+	Description string    `gorm:"size:255" json:"description"`
+}
+
+// PostMeta 文章自定义字段值，统一以文本存储
+type PostMeta struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	PostID    uint      `gorm:"not null;uniqueIndex:idx_post_meta_post_key" json:"post_id"`
+	MetaKey   string    `gorm:"size:50;not null;uniqueIndex:idx_post_meta_post_key;index:idx_post_meta_key_value,priority:1" json:"key"`
+	Value     string    `gorm:"size:1000;index:idx_post_meta_key_value,priority:2,length:191" json:"value"`
+}
+
+// PostLike 用户点赞记录，每个用户对同一篇文章只能点赞一次
+type PostLike struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_post_like_user_post" json:"user_id"`
+	PostID    uint      `gorm:"not null;uniqueIndex:idx_post_like_user_post;index" json:"post_id"`
+}
+
+// PostEditLock 文章编辑锁 (建议性锁，提示多人同时编辑同一篇文章)
+type PostEditLock struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PostID      uint      `gorm:"not null;uniqueIndex" json:"post_id"`
+	UserID      uint      `gorm:"not null" json:"user_id"`
+	User        User      `json:"user"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`            // 持有者最近一次心跳时间
+	ExpiresAt   time.Time `gorm:"index" json:"expires_at"` // 超过该时间未续期则锁自动失效
+}
+
+// PostAutosave 文章自动保存草稿，每个用户对每篇文章保留一份，不影响已发布内容
+type PostAutosave struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PostID      uint      `gorm:"not null;uniqueIndex:idx_post_autosave_post_user" json:"post_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_post_autosave_post_user" json:"user_id"`
+	Title       string    `gorm:"size:200" json:"title"`
+	Content     string    `gorm:"type:text" json:"content"`
+	Excerpt     string    `gorm:"size:500" json:"excerpt"`
+	CoverImage  string    `gorm:"size:255" json:"cover_image"`
+	BaseVersion int       `json:"base_version"` // 自动保存时文章的版本号，发布时用于冲突检测
+}
+
+// PostReviewNote 文章审核意见，可针对正文中的某段内容 (行内批注)
+type PostReviewNote struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	PostID    uint      `gorm:"not null;index" json:"post_id"`
+	UserID    uint      `gorm:"not null" json:"user_id"`
+	User      User      `json:"user"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	Quote     string    `gorm:"size:500" json:"quote"`         // 批注针对的原文片段
+	Offset    *int      `json:"offset"`                        // 原文片段在正文中的起始位置，可为空
+	Resolved  bool      `gorm:"default:false" json:"resolved"` // 是否已处理
 }
 
 // Comment 评论
 type Comment struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-	Content   string         `gorm:"type:text;not null" json:"content"`
-	PostID    uint           `json:"post_id"`
-	Post      Post           `json:"-"`
-	UserID    uint           `json:"user_id"`
-	User      User           `json:"user"`
-	ParentID  *uint          `json:"parent_id"` // 父评论ID，用于回复功能
-	Parent    *Comment       `gorm:"foreignKey:ParentID" json:"-"`
-	Replies   []Comment      `gorm:"foreignKey:ParentID" json:"replies"`
-	Status    string         `gorm:"size:20;default:'pending'" json:"status"` // pending, approved, rejected
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	Content       string         `gorm:"type:text;not null" json:"content"`
+	PostID        uint           `json:"post_id"`
+	Post          Post           `json:"-"`
+	UserID        *uint          `json:"user_id"` // 游客评论为空
+	User          *User          `json:"user,omitempty"`
+	GuestName     string         `gorm:"size:50" json:"guest_name,omitempty"`     // 游客昵称
+	GuestEmail    string         `gorm:"size:100" json:"guest_email,omitempty"`   // 游客邮箱，仅审核接口返回
+	GuestWebsite  string         `gorm:"size:255" json:"guest_website,omitempty"` // 游客个人网站
+	AvatarHash    string         `gorm:"size:64" json:"avatar_hash,omitempty"`    // 游客邮箱的 Gravatar 哈希，用于显示头像
+	ParentID      *uint          `json:"parent_id"`                               // 父评论ID，用于回复功能
+	Parent        *Comment       `gorm:"foreignKey:ParentID" json:"-"`
+	Replies       []Comment      `gorm:"foreignKey:ParentID" json:"replies"`
+	ReplyCount    int            `gorm:"-" json:"reply_count"`                          // 已通过审核的直接回复数，不入库
+	Status        string         `gorm:"size:20;default:'pending';index" json:"status"` // pending, approved, rejected, spam
+	EditedAt      *time.Time     `json:"edited_at"`                                     // 最近一次编辑时间
+	IsDeleted     bool           `gorm:"default:false" json:"is_deleted"`               // 已被作者删除但仍有回复，保留为占位以维持回复结构
+	ModeratedByID *uint          `json:"moderated_by_id,omitempty"`                     // 最近一次审核操作的管理员
+	ModeratedAt   *time.Time     `json:"moderated_at,omitempty"`
+	IP            string         `gorm:"size:45;index" json:"ip,omitempty"`          // 发表评论的IP，仅审核接口返回
+	SpamScore     int            `gorm:"default:0" json:"spam_score,omitempty"`      // 垃圾评论检测分数，仅审核接口返回
+	SpamReasons   string         `gorm:"size:1000" json:"spam_reasons,omitempty"`    // 命中的检测规则，仅审核接口返回
+	ContentHash   string         `gorm:"size:64;index" json:"-"`                     // 评论内容的 SHA-256，用于检测重复评论
+	PostInfo      *PostSummary   `gorm:"-" json:"post,omitempty"`                    // 所属文章摘要，仅审核队列返回，不入库
+	Score         int            `gorm:"default:0;index" json:"score"`               // 得分 (赞同数)，用于按热度排序
+	Reactions     map[string]int `gorm:"-" json:"reactions,omitempty"`               // 各类反应的数量，不入库
+	MyReactions   []string       `gorm:"-" json:"my_reactions,omitempty"`            // 当前用户的反应，仅登录时返回，不入库
+	AutoHidden    bool           `gorm:"default:false" json:"auto_hidden,omitempty"` // 因举报数达到阈值被自动隐藏，等待处理，仅审核接口返回
+}
+
+// CommentRevision 评论的编辑历史，保存每次编辑前的内容
+type CommentRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"` // 编辑时间
+	CommentID uint      `gorm:"not null;index" json:"comment_id"`
+	UserID    uint      `json:"user_id"` // 执行编辑的用户，游客编辑时为0
+	Content   string    `gorm:"type:text;not null" json:"content"`
+}
+
+// CommentReaction 用户对评论的反应 (赞同或表情)，同一用户对同一评论的每种反应只记录一次
+type CommentReaction struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_comment_reaction_user_comment_type" json:"user_id"`
+	CommentID uint      `gorm:"not null;uniqueIndex:idx_comment_reaction_user_comment_type;index" json:"comment_id"`
+	Type      string    `gorm:"size:20;not null;uniqueIndex:idx_comment_reaction_user_comment_type" json:"type"`
+}
+
+// CommentReport 用户对评论的举报，同一用户对同一评论只能举报一次
+type CommentReport struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	CommentID    uint       `gorm:"not null;uniqueIndex:idx_comment_report_user_comment;index" json:"comment_id"`
+	Comment      *Comment   `json:"comment,omitempty"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_comment_report_user_comment" json:"user_id"`
+	User         User       `json:"user"`
+	Reason       string     `gorm:"size:20;not null" json:"reason"`             // spam, abuse, harassment, off_topic, other
+	Detail       string     `gorm:"size:500" json:"detail"`                     // 举报说明
+	Status       string     `gorm:"size:20;default:'open';index" json:"status"` // open, resolved, dismissed
+	Resolution   string     `gorm:"size:20" json:"resolution,omitempty"`        // 处理操作: reject, spam, dismiss，评论被删除时为 deleted
+	ResolvedByID *uint      `json:"resolved_by_id,omitempty"`                   // 处理举报的管理员
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Series 文章系列 (如多篇连载的教程)
+type Series struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	Title       string         `gorm:"size:200;not null" json:"title"`
+	Slug        string         `gorm:"size:200;not null;unique" json:"slug"`
+	Description string         `gorm:"size:500" json:"description"`
+	CoverImage  string         `gorm:"size:255" json:"cover_image"`
+	Items       []SeriesPost   `gorm:"foreignKey:SeriesID" json:"items,omitempty"`
+}
+
+// SeriesPost 系列与文章的有序关联，一篇文章最多属于一个系列
+type SeriesPost struct {
+	ID       uint `gorm:"primaryKey" json:"id"`
+	SeriesID uint `gorm:"not null;index" json:"series_id"`
+	PostID   uint `gorm:"not null;uniqueIndex" json:"post_id"`
+	Position int  `gorm:"not null;default:0" json:"position"` // 在系列中的顺序，从1开始
+	Post     Post `json:"-"`
+}
+
+// CommentSettings 文章实际生效的评论设置
+type CommentSettings struct {
+	Policy        string     `json:"policy"`          // open, closed, registered
+	AutoCloseDays int        `json:"auto_close_days"` // 0 表示不自动关闭
+	Moderation    string     `json:"moderation"`      // required, auto
+	ClosesAt      *time.Time `json:"closes_at"`       // 评论自动关闭的时间
+	Open          bool       `json:"open"`            // 当前是否接受新评论
+}
+
+// PostSummary 文章摘要信息，用于导航链接
+type PostSummary struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// PostTranslation 文章的其他语言版本链接
+type PostTranslation struct {
+	ID    uint   `json:"id"`
+	Lang  string `json:"lang"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// SeriesNav 文章在系列中的导航信息 (第N篇/共M篇、上一篇、下一篇)
+type SeriesNav struct {
+	ID    uint         `json:"id"`
+	Title string       `json:"title"`
+	Slug  string       `json:"slug"`
+	Part  int          `json:"part"`
+	Total int          `json:"total"`
+	Prev  *PostSummary `json:"prev"`
+	Next  *PostSummary `json:"next"`
 }
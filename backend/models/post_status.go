@@ -0,0 +1,49 @@
+package models
+
+// 文章状态
+const (
+	PostStatusDraft            = "draft"             // 草稿
+	PostStatusPendingReview    = "pending_review"    // 待审核
+	PostStatusChangesRequested = "changes_requested" // 审核要求修改
+	PostStatusPublished        = "published"         // 已发布
+)
+
+// postStatusTransitions 文章状态机：允许的状态流转，值为 true 表示该流转只能由审核操作或管理员触发，
+// 进入 published 的流转都需要审核通过或由管理员直接发布
+var postStatusTransitions = map[string]map[string]bool{
+	PostStatusDraft: {
+		PostStatusDraft:         false,
+		PostStatusPendingReview: false, // 提交审核
+		PostStatusPublished:     true,  // 管理员直接发布
+	},
+	PostStatusPendingReview: {
+		PostStatusPendingReview:    false,
+		PostStatusDraft:            false, // 作者撤回审核
+		PostStatusPublished:        true,  // 审核通过
+		PostStatusChangesRequested: true,  // 审核要求修改
+	},
+	PostStatusChangesRequested: {
+		PostStatusChangesRequested: false,
+		PostStatusDraft:            false,
+		PostStatusPendingReview:    false, // 修改后重新提交审核
+	},
+	PostStatusPublished: {
+		PostStatusPublished: false,
+		PostStatusDraft:     false, // 撤回发布
+	},
+}
+
+// IsValidPostStatus 判断是否为有效的文章状态
+func IsValidPostStatus(status string) bool {
+	_, ok := postStatusTransitions[status]
+	return ok
+}
+
+// CanTransitionPostStatus 判断文章状态能否从 from 流转到 to，byReview 表示是否由审核操作或管理员触发
+func CanTransitionPostStatus(from, to string, byReview bool) bool {
+	if from == "" {
+		from = PostStatusDraft
+	}
+	reviewOnly, ok := postStatusTransitions[from][to]
+	return ok && (!reviewOnly || byReview)
+}
@@ -0,0 +1,46 @@
+package models
+
+import "testing"
+
+func TestCanTransitionPostStatus(t *testing.T) {
+	tests := []struct {
+		from, to string
+		byReview bool
+		want     bool
+	}{
+		{"", PostStatusDraft, false, true},
+		{"", PostStatusPendingReview, false, true},
+		{"", PostStatusPublished, false, false},
+		{PostStatusDraft, PostStatusPendingReview, false, true},
+		{PostStatusDraft, PostStatusPublished, false, false},
+		{PostStatusDraft, PostStatusPublished, true, true},
+		{PostStatusDraft, PostStatusChangesRequested, true, false},
+		{PostStatusPendingReview, PostStatusDraft, false, true},
+		{PostStatusPendingReview, PostStatusPublished, false, false},
+		{PostStatusPendingReview, PostStatusPublished, true, true},
+		{PostStatusPendingReview, PostStatusChangesRequested, false, false},
+		{PostStatusPendingReview, PostStatusChangesRequested, true, true},
+		{PostStatusChangesRequested, PostStatusPendingReview, false, true},
+		{PostStatusChangesRequested, PostStatusPublished, true, false},
+		{PostStatusPublished, PostStatusDraft, false, true},
+		{PostStatusPublished, PostStatusPublished, false, true},
+		{PostStatusPublished, PostStatusPublished, true, true},
+		{PostStatusPublished, PostStatusPendingReview, false, false},
+		{"unknown", PostStatusDraft, false, false},
+		{PostStatusDraft, "unknown", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionPostStatus(tt.from, tt.to, tt.byReview); got != tt.want {
+			t.Errorf("CanTransitionPostStatus(%q, %q, %v) = %v, want %v", tt.from, tt.to, tt.byReview, got, tt.want)
+		}
+	}
+}
+
+func TestPostStatusPublishedRequiresReview(t *testing.T) {
+	for from := range postStatusTransitions {
+		if from != PostStatusPublished && CanTransitionPostStatus(from, PostStatusPublished, false) {
+			t.Errorf("%s -> %s should only be allowed by review", from, PostStatusPublished)
+		}
+	}
+}
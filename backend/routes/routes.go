@@ -19,6 +19,8 @@ func RegisterRoutes(h *server.Hertz) {
 	registerCategoryRoutes(apiGroup)
 	registerTagRoutes(apiGroup)
 	registerCommentRoutes(apiGroup)
+	registerSeriesRoutes(apiGroup)
+	registerPostMetaFieldRoutes(apiGroup)
 	registerMiscRoutes(apiGroup) // 添加杂项路由注册
 }
 
@@ -46,16 +48,53 @@ func registerAuthRoutes(group *route.RouterGroup) {
 func registerPostRoutes(group *route.RouterGroup) {
 	posts := group.Group("/posts")
 
-	posts.GET("", api.GetPosts)                                 // 获取文章列表
-	posts.GET("/search", api.SearchPosts)                       // 全文搜索文章
-	posts.GET("/:id", api.GetPost)                              // 获取文章详情
-	posts.POST("/:id/like", middleware.JWTAuth(), api.LikePost) // 点赞文章
+	posts.GET("", middleware.OptionalJWTAuth(), api.GetPosts)           // 获取文章列表
+	posts.GET("/search", middleware.OptionalJWTAuth(), api.SearchPosts) // 全文搜索文章
+	posts.GET("/:id", middleware.OptionalJWTAuth(), api.GetPost)        // 获取文章详情
+	posts.GET("/:id/related", api.GetRelatedPosts)                      // 获取相关文章推荐
+	posts.POST("/:id/like", middleware.JWTAuth(), api.LikePost)         // 点赞文章
+	posts.DELETE("/:id/like", middleware.JWTAuth(), api.UnlikePost)     // 取消点赞文章
 
 	// 管理员权限路由
 	adminPosts := posts.Group("", middleware.JWTAuth(), middleware.AdminAuth())
-	adminPosts.POST("", api.CreatePost)       // 创建文章
-	adminPosts.PUT("/:id", api.UpdatePost)    // 更新文章
-	adminPosts.DELETE("/:id", api.DeletePost) // 删除文章
+	adminPosts.POST("", api.CreatePost)                               // 创建文章
+	adminPosts.DELETE("/:id", api.DeletePost)                         // 删除文章
+	adminPosts.PUT("/:id/pin", api.PinPost)                           // 置顶/取消置顶文章
+	adminPosts.PUT("/:id/feature", api.FeaturePost)                   // 设置/取消精选文章
+	adminPosts.PUT("/:id/authors", api.UpdatePostAuthors)             // 设置共同作者
+	adminPosts.POST("/:id/translations", api.LinkPostTranslation)     // 关联翻译文章
+	adminPosts.DELETE("/:id/translations", api.UnlinkPostTranslation) // 移出翻译分组
+	adminPosts.PUT("/:id/meta", api.SetPostMeta)                      // 设置自定义字段值
+	adminPosts.POST("/bulk", api.BulkUpdatePosts)                     // 批量操作文章
+	adminPosts.PUT("/pinned/order", api.ReorderPinnedPosts)           // 调整置顶文章顺序
+	adminPosts.GET("/trash", api.GetTrashedPosts)                     // 获取回收站文章列表
+	adminPosts.POST("/:id/restore", api.RestorePost)                  // 从回收站恢复文章
+	adminPosts.DELETE("/:id/purge", api.PurgePost)                    // 永久删除回收站中的文章
+
+	// 作者、共同作者或管理员可用的编辑路由，权限由 canEditPost 检查
+	editorPosts := posts.Group("", middleware.JWTAuth())
+	editorPosts.PUT("/:id", api.UpdatePost)                        // 更新文章
+	editorPosts.GET("/:id/lock", api.GetPostLock)                  // 获取编辑锁状态
+	editorPosts.POST("/:id/lock", api.AcquirePostLock)             // 获取编辑锁
+	editorPosts.PUT("/:id/lock/heartbeat", api.HeartbeatPostLock)  // 编辑锁心跳续期
+	editorPosts.DELETE("/:id/lock", api.ReleasePostLock)           // 释放编辑锁
+	editorPosts.POST("/:id/lock/force", api.ForceTakePostLock)     // 强制接管编辑锁
+	editorPosts.GET("/:id/autosave", api.GetAutosave)              // 获取自动保存草稿
+	editorPosts.PUT("/:id/autosave", api.SaveAutosave)             // 自动保存草稿
+	editorPosts.POST("/:id/autosave/publish", api.PublishAutosave) // 发布自动保存草稿
+	editorPosts.DELETE("/:id/autosave", api.DiscardAutosave)       // 丢弃自动保存草稿
+
+	// 文章审核流程
+	adminPosts.POST("/:id/review/submit", api.SubmitPostForReview)                 // 提交审核
+	adminPosts.PUT("/:id/review/reviewer", api.AssignPostReviewer)                 // 指定审核人
+	adminPosts.POST("/:id/review/approve", api.ApprovePost)                        // 审核通过并发布
+	adminPosts.POST("/:id/review/request-changes", api.RequestPostChanges)         // 审核要求修改
+	adminPosts.GET("/:id/review/notes", api.GetPostReviewNotes)                    // 获取审核意见
+	adminPosts.POST("/:id/review/notes", api.AddPostReviewNote)                    // 添加审核意见
+	adminPosts.PUT("/:id/review/notes/:noteId/resolve", api.ResolvePostReviewNote) // 标记审核意见已处理
+
+	// 文章评论设置 (开放/关闭/仅登录用户、自动关闭天数、审核方式)
+	adminPosts.PUT("/:id/comment-settings", api.UpdatePostCommentSettings)
 }
 
 // 分类相关路由
@@ -86,25 +125,69 @@ func registerTagRoutes(group *route.RouterGroup) {
 	adminTags.DELETE("/:id", api.DeleteTag)
 }
 
+// 系列相关路由
+func registerSeriesRoutes(group *route.RouterGroup) {
+	series := group.Group("/series")
+	series.GET("", api.GetSeriesList) // 公开获取系列列表
+	series.GET("/:id", api.GetSeries) // 公开获取系列详情及文章
+
+	// 管理员权限路由
+	adminSeries := series.Group("", middleware.JWTAuth(), middleware.AdminAuth())
+	adminSeries.POST("", api.CreateSeries)
+	adminSeries.PUT("/:id", api.UpdateSeries)
+	adminSeries.DELETE("/:id", api.DeleteSeries)
+}
+
+// 文章自定义字段定义路由 (仅管理员)
+func registerPostMetaFieldRoutes(group *route.RouterGroup) {
+	fields := group.Group("/post-meta-fields", middleware.JWTAuth(), middleware.AdminAuth())
+	fields.GET("", api.GetMetaFields)
+	fields.POST("", api.CreateMetaField)
+	fields.PUT("/:id", api.UpdateMetaField)
+	fields.DELETE("/:id", api.DeleteMetaField)
+}
+
 // 评论相关路由
 func registerCommentRoutes(group *route.RouterGroup) {
 	comments := group.Group("/comments") // 评论相关路由
 
-	// 创建评论 (需要登录)
-	comments.POST("/post/:postId", middleware.JWTAuth(), api.AddComment)
+	// 创建评论 (登录用户，或开启 COMMENT_ALLOW_GUESTS 后的游客)
+	comments.POST("/post/:postId", middleware.OptionalJWTAuth(), api.AddComment)
+	// 公开获取文章的评论树
+	comments.GET("/post/:postId", middleware.OptionalJWTAuth(), api.GetPostComments)
 	// comments.GET("", api.GetComments) // 获取评论列表 (通常在文章详情中获取)
 	// comments.GET("/:id", api.GetComment) // 获取单个评论详情
-	// comments.PUT("/:id", api.UpdateComment) // 更新评论 (通常不允许用户更新)
-	// comments.DELETE("/:id", api.DeleteComment) // 删除评论 (用户或管理员)
+	comments.PUT("/:id", middleware.OptionalJWTAuth(), api.UpdateComment)                 // 编辑评论 (作者在限定时间内，游客凭签名 Cookie)
+	comments.DELETE("/:id", middleware.OptionalJWTAuth(), api.DeleteComment)              // 删除评论 (作者、游客或管理员)
+	comments.GET("/:id/revisions", middleware.OptionalJWTAuth(), api.GetCommentRevisions) // 评论编辑历史
+
+	// 评论反应 (赞同或表情): upvote, heart, laugh, hooray, confused, eyes
+	comments.PUT("/:id/reactions/:type", middleware.JWTAuth(), api.AddCommentReaction)
+	comments.DELETE("/:id/reactions/:type", middleware.JWTAuth(), api.RemoveCommentReaction)
+
+	// 举报评论
+	comments.POST("/:id/report", middleware.JWTAuth(), api.ReportComment)
 
 	// 管理员权限路由
 	adminComments := group.Group("/admin/comments", middleware.JWTAuth(), middleware.AdminAuth())
-	adminComments.PUT("/:id/approve", api.ApproveComment) // 审核通过评论
-	// adminComments.PUT("/:id/reject", api.RejectComment)   // TODO: 实现拒绝评论功能
+	adminComments.GET("", api.GetModerationQueue)                // 审核队列 (按状态、文章、用户、日期过滤)
+	adminComments.GET("/pending", api.GetPendingComments)        // 待审核评论
+	adminComments.POST("/bulk", api.BulkModerateComments)        // 批量审核
+	adminComments.GET("/users/:id/trust", api.GetCommenterTrust) // 获取用户评论信任级别
+	adminComments.PUT("/users/:id/trust", api.SetCommenterTrust) // 标记用户为可信/始终审核
+	adminComments.PUT("/:id/approve", api.ApproveComment)        // 审核通过评论
+	adminComments.PUT("/:id/reject", api.RejectComment)          // 拒绝评论
+	adminComments.PUT("/:id/spam", api.MarkCommentSpam)          // 标记为垃圾评论
+	adminComments.PUT("/:id/restore", api.RestoreComment)        // 恢复到待审核状态
+
+	// 评论举报队列及处理 (reject、spam 修改评论状态，dismiss 驳回举报)
+	adminComments.GET("/reports", api.GetCommentReports)
+	adminComments.PUT("/reports/:id/resolve", api.ResolveCommentReport)
 }
 
 // 杂项路由 (首页数据、归档等)
 func registerMiscRoutes(group *route.RouterGroup) {
 	group.GET("/home", api.GetHomePageData)   // 获取首页数据
 	group.GET("/archive", api.GetArchiveData) // 获取归档数据
+	group.GET("/seo", api.GetSEOMeta)         // 获取前台页面的 meta 标签和结构化数据
 }
@@ -7,6 +7,35 @@ import { postAPI } from '../api';
 
 const { Title, Paragraph } = Typography;
 
+// 评论者头像：登录用户使用账号头像，游客使用邮箱哈希对应的 Gravatar 头像
+const commentAvatar = (comment) => {
+  if (comment.user) {
+    return comment.user.avatar || 'https://joeschmoe.io/api/v1/random';
+  }
+  if (comment.avatar_hash) {
+    return `https://www.gravatar.com/avatar/${comment.avatar_hash}?d=identicon`;
+  }
+  return 'https://joeschmoe.io/api/v1/random';
+};
+
+// 评论者名称：游客显示其填写的昵称，有个人网站时链接到该网站
+const commentAuthor = (comment) => {
+  if (comment.user) {
+    return comment.user.nickname || comment.user.username;
+  }
+  if (!comment.guest_name) {
+    return '匿名用户';
+  }
+  if (comment.guest_website) {
+    return (
+      <a href={comment.guest_website} target="_blank" rel="nofollow ugc noopener noreferrer">
+        {comment.guest_name}
+      </a>
+    );
+  }
+  return comment.guest_name;
+};
+
 const PostDetailPage = () => {
   const { id } = useParams();
   const [loading, setLoading] = useState(true);
@@ -171,10 +200,10 @@ const PostDetailPage = () => {
           renderItem={comment => (
             <List.Item>
               <List.Item.Meta
-                avatar={<Avatar src={comment.user?.avatar || 'https://joeschmoe.io/api/v1/random'} />}
+                avatar={<Avatar src={commentAvatar(comment)} />}
                 title={
                   <Space split="|">
-                    <span>{comment.user?.username || '匿名用户'}</span>
+                    <span>{commentAuthor(comment)}</span>
                     <span style={{ fontSize: '12px', color: '#8c8c8c' }}>
                       {new Date(comment.created_at).toLocaleString('zh-CN')}
                     </span>
@@ -17,6 +17,7 @@ const UpdatePostPage = () => {
   const [categories, setCategories] = useState([]); // State for categories
   const [tags, setTags] = useState([]); // State for tags
   const [dataLoading, setDataLoading] = useState(true); // Loading state for categories/tags
+  const [version, setVersion] = useState(null); // 编辑所基于的文章版本，用于并发编辑冲突检测
   const navigate = useNavigate();
   const { id } = useParams();
 
@@ -52,6 +53,7 @@ const UpdatePostPage = () => {
           throw new Error('获取文章数据格式不正确');
         }
         const postData = postRes.data || postRes;
+        setVersion(postData.version ?? null);
         form.setFieldsValue({
           title: postData.title || '',
           content: postData.content || '',
@@ -125,6 +127,7 @@ const UpdatePostPage = () => {
         status: 'published',
         categories: values.categories || [],
         tags: values.tags || [],
+        version,
       };
 
       // 添加错误处理和重试逻辑
@@ -138,6 +141,11 @@ const UpdatePostPage = () => {
           throw new Error('更新文章失败');
         }
       } catch (apiError) {
+        // 版本冲突说明文章已被他人修改，不能重试覆盖
+        if (apiError.response?.status === 409) {
+          message.error('文章已被其他人修改，请刷新页面后基于最新版本重新编辑');
+          return;
+        }
         console.error('API调用失败，尝试直接发送请求:', apiError);
         // 如果API调用失败，尝试直接使用fetch
         try {